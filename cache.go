@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/hiroyannnn/gh-pr-digest/config"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the API response cache",
+		// 設定ファイルに誤りがあってもキャッシュを削除できるように既定値は適用しない
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Delete all cached API responses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := config.CacheDir()
+			if err := client.ClearCache(dir); err != nil {
+				return err
+			}
+			fmt.Printf("キャッシュを削除しました: %s\n", dir)
+			return nil
+		},
+	})
+
+	return cmd
+}
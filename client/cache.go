@@ -0,0 +1,183 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cacheTransport はGETリクエストのレスポンスをディスクに保存するRoundTripper。
+// 有効期限内はAPIを呼ばずに保存したレスポンスを返し、期限を過ぎたものは
+// If-None-Match/If-Modified-Sinceで再検証する（304はレート制限に数えられない）。
+type cacheTransport struct {
+	dir  string
+	base http.RoundTripper
+	logf func(format string, args ...interface{})
+}
+
+type cacheEntry struct {
+	URL      string      `json:"url"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+// cacheTTL はエンドポイントの種類ごとに、再検証せずにキャッシュを使う期間を返す
+func cacheTTL(path string) time.Duration {
+	switch {
+	case strings.HasSuffix(path, "/user"):
+		// 認証ユーザーはほとんど変わらない
+		return 24 * time.Hour
+	case strings.Contains(path, "/check-runs"), strings.HasSuffix(path, "/status"):
+		// CIの状態はすぐに変わるため毎回再検証する
+		return 0
+	case strings.Contains(path, "/search/"):
+		return time.Minute
+	default:
+		// PRの詳細、コミット、イベント、レビュー、コメント
+		return 5 * time.Minute
+	}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	entry := t.load(key)
+	if entry != nil && timeNow().Sub(entry.StoredAt) < cacheTTL(req.URL.Path) {
+		t.logf("キャッシュ使用: %s\n", req.URL.Path)
+		return entry.response(req), nil
+	}
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		t.logf("キャッシュ再検証: %s\n", req.URL.Path)
+		// レート制限などのヘッダーは最新のものにする
+		for k, v := range resp.Header {
+			entry.Header[k] = v
+		}
+		entry.StoredAt = timeNow()
+		t.save(key, entry)
+		return entry.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("レスポンスの読み込みに失敗: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.save(key, &cacheEntry{
+		URL:      req.URL.String(),
+		Header:   resp.Header.Clone(),
+		Body:     body,
+		StoredAt: timeNow(),
+	})
+	return resp, nil
+}
+
+// cacheKey は認証情報ごとにURLからキャッシュのキーを作る
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\n" + req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *cacheTransport) path(key string) string {
+	return filepath.Join(t.dir, key[:2], key+".json")
+}
+
+// load は保存したレスポンスを読み込む。存在しない、または壊れている場合はnilを返す
+func (t *cacheTransport) load(key string) *cacheEntry {
+	data, err := os.ReadFile(t.path(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Header == nil {
+		return nil
+	}
+	return &entry
+}
+
+// save はレスポンスを保存する。保存に失敗してもリクエストは失敗させない
+func (t *cacheTransport) save(key string, entry *cacheEntry) {
+	if err := writeCacheEntry(t.path(key), entry); err != nil {
+		t.logf("キャッシュの保存に失敗: %v\n", err)
+	}
+}
+
+func writeCacheEntry(path string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// 並行して書き込んでも壊れないように一時ファイルから置き換える
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// ClearCache は保存したレスポンスをすべて削除する
+func ClearCache(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("キャッシュの削除に失敗: %w", err)
+	}
+	return nil
+}
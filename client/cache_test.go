@@ -0,0 +1,117 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheTransport(t *testing.T) {
+	now := time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<https://api.github.com/search/issues?page=2>; rel="next"`)
+		io.WriteString(w, `{"number":1}`)
+	}))
+	defer server.Close()
+
+	transport := &cacheTransport{
+		dir:  t.TempDir(),
+		base: http.DefaultTransport,
+		logf: func(string, ...interface{}) {},
+	}
+	httpClient := &http.Client{Transport: transport}
+
+	get := func(path string) (string, http.Header) {
+		t.Helper()
+		resp, err := httpClient.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want 200", resp.StatusCode)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), resp.Header
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		elapsed         time.Duration
+		wantRequests    int
+		wantNotModified int
+	}{
+		{name: "初回はAPIから取得", path: "/repos/owner/repo/pulls/1", wantRequests: 1},
+		{name: "有効期限内はキャッシュを使用", path: "/repos/owner/repo/pulls/1", elapsed: time.Minute, wantRequests: 1},
+		{name: "有効期限切れは再検証", path: "/repos/owner/repo/pulls/1", elapsed: 10 * time.Minute, wantRequests: 2, wantNotModified: 1},
+		{name: "再検証後は有効期限が延びる", path: "/repos/owner/repo/pulls/1", elapsed: time.Minute, wantRequests: 2, wantNotModified: 1},
+		{name: "CIの状態は毎回再検証", path: "/repos/owner/repo/commits/abc/status", wantRequests: 3, wantNotModified: 1},
+		{name: "CIの状態の2回目", path: "/repos/owner/repo/commits/abc/status", wantRequests: 4, wantNotModified: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+			body, header := get(tt.path)
+			if body != `{"number":1}` {
+				t.Errorf("body = %s", body)
+			}
+			if header.Get("Link") == "" {
+				t.Error("Link header was not preserved")
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if notModified != tt.wantNotModified {
+				t.Errorf("304 responses = %d, want %d", notModified, tt.wantNotModified)
+			}
+		})
+	}
+}
+
+func TestCacheTransport_Authorization(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		io.WriteString(w, `{"login":"`+r.Header.Get("Authorization")+`"}`)
+	}))
+	defer server.Close()
+
+	transport := &cacheTransport{
+		dir:  t.TempDir(),
+		base: http.DefaultTransport,
+		logf: func(string, ...interface{}) {},
+	}
+
+	for _, token := range []string{"token a", "token b", "token a"} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/user", nil)
+		req.Header.Set("Authorization", token)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != `{"login":"`+token+`"}` {
+			t.Errorf("body = %s, want response for %s", body, token)
+		}
+	}
+	// 認証情報ごとに別のキャッシュを使う
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
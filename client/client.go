@@ -2,7 +2,7 @@ package client
 
 import (
 	"fmt"
-	"net/url"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -14,40 +14,201 @@ import (
 // テスト用にtime.Now()をモック可能にする
 var timeNow = time.Now
 
+// Backend はPRの取得に使用するAPI
+type Backend string
+
+const (
+	// BackendREST は検索結果ごとにREST APIで詳細情報を取得する
+	BackendREST Backend = "rest"
+	// BackendGraphQL は検索結果と詳細情報をGraphQL APIでまとめて取得する
+	BackendGraphQL Backend = "graphql"
+)
+
 type PullRequest struct {
-	Title      string    `json:"title"`
-	URL        string    `json:"url"`
-	HTMLURL    string    `json:"html_url"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	State      string    `json:"state"`
-	Merged     bool      `json:"merged"`
-	Draft      bool      `json:"draft"`
-	Number     int       `json:"number"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url"`
+	HTMLURL    string     `json:"html_url"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	State      string     `json:"state"`
+	Merged     bool       `json:"merged"`
+	Draft      bool       `json:"draft"`
+	Number     int        `json:"number"`
+	Author     string     `json:"author"`
 	Repository struct {
 		FullName string `json:"full_name"`
 	} `json:"repository"`
+	// ベースブランチ
+	Base string `json:"base,omitempty"`
+	// PRを取得したホスト（github.comやGitHub Enterprise Serverのホスト名）
+	Host      string   `json:"host"`
+	Labels    []Label  `json:"labels,omitempty"`
+	Milestone string   `json:"milestone,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	// 本文のキーワード（Closes #123など）とGraphQLのclosingIssuesReferencesから求めた、
+	// PRのマージでクローズされるIssue
+	ClosingIssues []IssueRef `json:"closing_issues,omitempty"`
+	// 変更量（追加・削除した行数、変更したファイル数、コミット数）
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+	Commits      int `json:"commits"`
+	// 変更量から決めたサイズ（XS/S/M/L/XL）
+	Size string `json:"size,omitempty"`
+	// 活動を判定したユーザー（チームのダイジェストではメンバーごとに異なる）
+	User string `json:"user"`
+	// 検索に一致した自分との関係
+	Roles []Role `json:"roles"`
+	// 期間内の自分の活動から判定した関わり方
+	Contribution Contribution `json:"contribution"`
+	Activities   []Activity   `json:"activities,omitempty"`
+	// 期間内の自分の最新のレビュー状態とコメント数（author以外の関係で検索した場合のみ）
+	ReviewState  string `json:"review_state,omitempty"`
+	CommentCount int    `json:"comment_count,omitempty"`
+	// オープンなPRのレビュー状況（SearchOptions.WithReviewStatusを指定した場合のみ）。
+	// RequestedReviewersはまだレビューしていない（レビュー待ちの）人とチーム
+	RequestedReviewers []string `json:"requested_reviewers,omitempty"`
+	Reviewers          []string `json:"reviewers,omitempty"`
+	Approvers          []string `json:"approvers,omitempty"`
+	// APPROVED、CHANGES_REQUESTED、REVIEW_REQUIREDのいずれか。
+	// GraphQLバックエンドではブランチ保護の設定を反映した値になる
+	ReviewDecision string `json:"review_decision,omitempty"`
+	// オープンなPRのheadのCIの状態と失敗したチェックの名前（SearchOptions.WithChecksを指定した場合のみ）
+	CheckState    string   `json:"check_state,omitempty"`
+	FailingChecks []string `json:"failing_checks,omitempty"`
+
+	// 取得済みのPRの詳細のうち、CIの状態とレビュー状況の取得で使い回す部分
+	status *pullStatus
+}
+
+// SearchOptions はPRの検索条件
+type SearchOptions struct {
+	Org string
+	// owner/repo形式のリポジトリ。複数指定するといずれかのリポジトリのPRを検索する。
+	// myorg/service-*のようなワイルドカードも指定できる
+	Repos []string
+	// ラベルやベースブランチなどの絞り込みの条件
+	Filters
+	// そのまま追加する検索の条件（language:goなど）。ValidateQueryで検証しておく
+	Query string
+	// ゼロ値の場合はローカルタイムゾーンでの今日とする
+	Range DateRange
+	// 空の場合は自分が作成したPRを検索する
+	Roles []Role
+	// 検索の対象にするユーザー。空の場合は認証ユーザー
+	User string
+	// 期間に関わらずオープンなPRだけを検索する
+	OpenOnly bool
+	// オープンなPRのCIの状態も取得する
+	WithChecks bool
+	// オープンなPRのレビュー状況も取得する
+	WithReviewStatus bool
+}
+
+func (o SearchOptions) dateRange() DateRange {
+	if o.Range.Until.IsZero() {
+		return Today(time.Local)
+	}
+	return o.Range
+}
+
+func (o SearchOptions) roles() []Role {
+	if len(o.Roles) == 0 {
+		return []Role{RoleAuthor}
+	}
+	return o.Roles
 }
 
 type PRClient struct {
-	client api.RESTClient
-	debug  bool
+	client  api.RESTClient
+	gql     api.GQLClient
+	host    string
+	backend Backend
+	debug   bool
 	// キャッシュの追加
 	userCache      *string
 	userCacheMux   sync.RWMutex
-	commitCache    map[string]bool
+	commitCache    map[string][]prCommit
 	commitCacheMux sync.RWMutex
+	// 取得結果に関する警告と、取得に失敗したPR
+	warnings    []string
+	failures    []PRError
+	warningsMux sync.Mutex
+	strict      bool
+	// 同時実行数の制限とレート制限の状態
+	limiter     *adaptiveLimiter
+	limiterOnce sync.Once
+	rateStats   *rateLimitStats
 }
 
-func NewPRClient() (*PRClient, error) {
-	client, err := gh.RESTClient(nil)
+// ClientOptions はPRClientの作成時の設定
+type ClientOptions struct {
+	// 接続するホスト（空の場合はDefaultHost）
+	Host string
+	// REST APIのレスポンスを保存するディレクトリ（空の場合はキャッシュしない）
+	CacheDir string
+	// PRの詳細を並列で取得するときの同時実行数の上限（0の場合はDefaultMaxConcurrency）
+	MaxConcurrency int
+}
+
+func NewPRClient(opts ClientOptions) (*PRClient, error) {
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+	c := &PRClient{
+		backend:     BackendREST,
+		commitCache: make(map[string][]prCommit),
+		limiter:     newAdaptiveLimiter(maxConcurrency),
+		rateStats:   newRateLimitStats(),
+	}
+
+	var transport http.RoundTripper = &retryTransport{
+		base:    http.DefaultTransport,
+		limiter: c.limiter,
+		stats:   c.rateStats,
+		logf:    c.debugPrint,
+		sleep:   sleepContext,
+	}
+	if opts.CacheDir != "" {
+		transport = &cacheTransport{
+			dir:  opts.CacheDir,
+			base: transport,
+			logf: c.debugPrint,
+		}
+	}
+
+	c.host = opts.Host
+	if c.host == "" {
+		c.host = DefaultHost()
+	}
+	client, err := gh.RESTClient(&api.ClientOptions{Host: c.host, Transport: transport})
 	if err != nil {
 		return nil, fmt.Errorf("GitHub クライアントの作成に失敗: %w", err)
 	}
-	return &PRClient{
-		client:      client,
-		commitCache: make(map[string]bool),
-	}, nil
+	gql, err := gh.GQLClient(&api.ClientOptions{Host: c.host, Transport: transport})
+	if err != nil {
+		return nil, fmt.Errorf("GitHub GraphQL クライアントの作成に失敗: %w", err)
+	}
+	c.client = client
+	c.gql = gql
+	return c, nil
+}
+
+// Host は接続するホストを返す
+func (c *PRClient) Host() string {
+	return c.host
+}
+
+func (c *PRClient) SetBackend(backend string) error {
+	switch Backend(backend) {
+	case BackendREST, BackendGraphQL:
+		c.backend = Backend(backend)
+		return nil
+	default:
+		return fmt.Errorf("不明なバックエンド: %s（rest/graphqlのいずれかを指定してください）", backend)
+	}
 }
 
 func (c *PRClient) SetDebug(debug bool) {
@@ -60,46 +221,78 @@ func (c *PRClient) debugPrint(format string, args ...interface{}) {
 	}
 }
 
-func (c *PRClient) FetchTodaysPRs(org, repo, since, until string) ([]PullRequest, error) {
-	query := buildSearchQuery(org, repo, since, until)
+// concurrency はPRの詳細の取得に使う同時実行数の制限を返す
+func (c *PRClient) concurrency() *adaptiveLimiter {
+	c.limiterOnce.Do(func() {
+		if c.limiter == nil {
+			c.limiter = newAdaptiveLimiter(DefaultMaxConcurrency)
+		}
+	})
+	return c.limiter
+}
+
+// RateLimitSummary はリクエスト数、リトライ回数、レート制限の残りをまとめて返す
+func (c *PRClient) RateLimitSummary() string {
+	if c.rateStats == nil {
+		return ""
+	}
+	return c.rateStats.summary()
+}
+
+func (c *PRClient) warnf(format string, args ...interface{}) {
+	c.warningsMux.Lock()
+	defer c.warningsMux.Unlock()
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// Warnings は取得時に発生した警告（検索結果の打ち切りなど）を返す
+func (c *PRClient) Warnings() []string {
+	c.warningsMux.Lock()
+	defer c.warningsMux.Unlock()
+	return append([]string(nil), c.warnings...)
+}
+
+func (c *PRClient) FetchTodaysPRs(opts SearchOptions) ([]PullRequest, error) {
+	rng := opts.dateRange()
 
 	// GitHub Search APIを使用してPRを検索
-	response := struct {
-		Items []struct {
-			Title      string    `json:"title"`
-			URL        string    `json:"url"`
-			HTMLURL    string    `json:"html_url"`
-			CreatedAt  time.Time `json:"created_at"`
-			UpdatedAt  time.Time `json:"updated_at"`
-			State      string    `json:"state"`
-			Draft      bool      `json:"draft"`
-			Number     int       `json:"number"`
-			Repository struct {
-				FullName string `json:"full_name"`
-				HTMLURL  string `json:"html_url"`
-			} `json:"repository"`
-		} `json:"items"`
-		Total int `json:"total_count"`
-	}{}
-
-	path := fmt.Sprintf("search/issues?%s", url.Values{
-		"q":        []string{query},
-		"sort":     []string{"updated"},
-		"order":    []string{"desc"},
-		"per_page": []string{"100"}, // 一度に取得するPR数を増やす
-	}.Encode())
+	items, err := c.searchPRs(opts)
+	if err != nil {
+		return nil, err
+	}
+	items = filterRepos(items, opts)
 
-	// デバッグ出力
-	c.debugPrint("APIパス: %s\n", path)
+	// GraphQLでは詳細情報も検索結果に含まれている
+	var prs []PullRequest
+	if c.backend == BackendGraphQL {
+		prs, err = c.buildGraphQLPRs(items, rng, opts.User)
+	} else {
+		prs, err = c.buildRESTPRs(items, rng, opts.User)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	err := c.client.Get(path, &response)
+	prs = filterPRs(prs, opts.Filters)
+
+	if opts.WithChecks || opts.WithReviewStatus {
+		if err := c.fetchStatuses(prs, opts.WithChecks, opts.WithReviewStatus); err != nil {
+			return nil, err
+		}
+	}
+	return prs, nil
+}
+
+// buildRESTPRs は検索結果ごとにREST APIで詳細情報を取得してPRを組み立てる
+// usernameが空の場合は認証ユーザーの活動を判定する
+func (c *PRClient) buildRESTPRs(items []searchItem, rng DateRange, username string) ([]PullRequest, error) {
+	username, err := c.resolveUser(username)
 	if err != nil {
-		return nil, fmt.Errorf("PRの取得に失敗: %w", err)
+		return nil, err
 	}
 
 	// デバッグ出力
-	c.debugPrint("検索結果: %d件\n", response.Total)
-	for i, item := range response.Items {
+	for i, item := range items {
 		repoFullName := extractRepoFullName(item.URL)
 		c.debugPrint("PR %d: [%s] %s (#%d)\n", i+1, repoFullName, item.Title, item.Number)
 		c.debugPrint("  URL: %s\n", item.URL)
@@ -109,136 +302,85 @@ func (c *PRClient) FetchTodaysPRs(org, repo, since, until string) ([]PullRequest
 	}
 
 	// 並列処理用のチャネルとエラーチャネルを作成
-	prChan := make(chan PullRequest, len(response.Items))
-	errChan := make(chan error, len(response.Items))
-	semaphore := make(chan struct{}, 10) // 同時実行数を制限
+	prChan := make(chan PullRequest, len(items))
+	errChan := make(chan PRError, len(items))
+	limiter := c.concurrency() // 同時実行数を制限
 
 	// 各PRの詳細情報を並列で取得
 	var wg sync.WaitGroup
-	for _, item := range response.Items {
+	for _, item := range items {
 		wg.Add(1)
-		go func(item struct {
-			Title      string    `json:"title"`
-			URL        string    `json:"url"`
-			HTMLURL    string    `json:"html_url"`
-			CreatedAt  time.Time `json:"created_at"`
-			UpdatedAt  time.Time `json:"updated_at"`
-			State      string    `json:"state"`
-			Draft      bool      `json:"draft"`
-			Number     int       `json:"number"`
-			Repository struct {
-				FullName string `json:"full_name"`
-				HTMLURL  string `json:"html_url"`
-			} `json:"repository"`
-		}) {
+		go func(item searchItem) {
 			defer wg.Done()
-			semaphore <- struct{}{}        // セマフォ取得
-			defer func() { <-semaphore }() // セマフォ解放
+			limiter.acquire()
+			defer limiter.release()
 
 			repoFullName := extractRepoFullName(item.URL)
 			if repoFullName == "" {
 				c.debugPrint("リポジトリ名の抽出に失敗: %s\n", item.URL)
-				errChan <- fmt.Errorf("リポジトリ名の抽出に失敗: %s", item.URL)
+				errChan <- newPRError("", item.Number, item.HTMLURL, fmt.Errorf("リポジトリ名の抽出に失敗: %s", item.URL))
 				return
 			}
 
-			// コミット情報を取得（必要な場合のみ）
-			hasMyCommit, err := c.hasMyCommitInRange(PullRequest{
+			pr := PullRequest{
 				Title:     item.Title,
 				URL:       convertToPullsURL(item.URL),
 				HTMLURL:   item.HTMLURL,
 				CreatedAt: item.CreatedAt,
 				UpdatedAt: item.UpdatedAt,
+				ClosedAt:  item.ClosedAt,
 				State:     item.State,
 				Merged:    false,
 				Draft:     item.Draft,
 				Number:    item.Number,
+				Author:    item.User.Login,
 				Repository: struct {
 					FullName string `json:"full_name"`
 				}{
 					FullName: repoFullName,
 				},
-			}, since, until)
-			if err != nil {
-				c.debugPrint("コミット情報の取得に失敗: %v\n", err)
-				errChan <- fmt.Errorf("コミット情報の取得に失敗: %w", err)
-				return
+				Labels:        item.Labels,
+				Milestone:     item.milestone(),
+				Assignees:     item.assignees(),
+				Host:          c.host,
+				ClosingIssues: item.closingIssueRefs(repoFullName),
+				Roles:         item.roles,
+				User:          username,
 			}
 
-			if item.Draft || hasMyCommit {
-				// マージ情報を取得（stateがclosedの場合のみ）
-				merged := false
-				if item.State == "closed" {
-					prPath := fmt.Sprintf("repos/%s/pulls/%d", repoFullName, item.Number)
-					c.debugPrint("マージ情報取得: %s\n", prPath)
-
-					var prDetail struct {
-						Merged bool `json:"merged"`
-					}
-					if err := c.client.Get(prPath, &prDetail); err != nil {
-						c.debugPrint("マージ情報の取得に失敗: %v\n", err)
-					} else {
-						merged = prDetail.Merged
-						c.debugPrint("マージ状態: %v\n", merged)
-					}
-				}
-
-				prChan <- PullRequest{
-					Title:     item.Title,
-					URL:       convertToPullsURL(item.URL),
-					HTMLURL:   item.HTMLURL,
-					CreatedAt: item.CreatedAt,
-					UpdatedAt: item.UpdatedAt,
-					State:     item.State,
-					Merged:    merged,
-					Draft:     item.Draft,
-					Number:    item.Number,
-					Repository: struct {
-						FullName string `json:"full_name"`
-					}{
-						FullName: repoFullName,
-					},
-				}
+			withReviews := false
+			for _, role := range item.roles {
+				withReviews = withReviews || role.needsReviews()
 			}
+
+			// 期間内の自分の活動を判定
+			if err := c.detectContribution(&pr, rng, withReviews, username); err != nil {
+				c.debugPrint("活動情報の取得に失敗: %v\n", err)
+				errChan <- newPRError(repoFullName, item.Number, item.HTMLURL, fmt.Errorf("活動情報の取得に失敗: %w", err))
+				return
+			}
+
+			prChan <- pr
 		}(item)
 	}
 
-	// 完了を待つためのゴルーチン
-	go func() {
-		wg.Wait()
-		close(prChan)
-		close(errChan)
-	}()
+	wg.Wait()
+	close(prChan)
+	close(errChan)
 
-	// 結果の収集
+	// 結果の収集（失敗したPRを除いて返す）
 	var prs []PullRequest
-	var errors []error
-	for {
-		select {
-		case pr, ok := <-prChan:
-			if !ok {
-				// エラーがあれば最初のエラーを返す
-				if len(errors) > 0 {
-					return nil, errors[0]
-				}
-				return prs, nil
-			}
-			prs = append(prs, pr)
-		case err, ok := <-errChan:
-			if !ok {
-				continue
-			}
-			errors = append(errors, err)
-		}
+	for pr := range prChan {
+		prs = append(prs, pr)
 	}
-}
-
-func extractRepoFullName(apiURL string) string {
-	parts := strings.Split(apiURL, "/")
-	if len(parts) >= 6 {
-		return fmt.Sprintf("%s/%s", parts[4], parts[5])
+	var failures []PRError
+	for err := range errChan {
+		failures = append(failures, err)
 	}
-	return ""
+	if err := c.collectFailures(failures); err != nil {
+		return nil, err
+	}
+	return prs, nil
 }
 
 // /issues URLを/pulls URLに変換する
@@ -246,6 +388,18 @@ func convertToPullsURL(issuesURL string) string {
 	return strings.Replace(issuesURL, "/issues/", "/pulls/", 1)
 }
 
+// resolveUser は指定したユーザー名を返す。空の場合は認証ユーザーのユーザー名を返す
+func (c *PRClient) resolveUser(username string) (string, error) {
+	if username != "" {
+		return username, nil
+	}
+	user, err := c.getUser()
+	if err != nil {
+		return "", fmt.Errorf("ユーザー情報の取得に失敗: %w", err)
+	}
+	return user, nil
+}
+
 func (c *PRClient) getUser() (string, error) {
 	c.userCacheMux.RLock()
 	if c.userCache != nil {
@@ -273,143 +427,32 @@ func (c *PRClient) getUser() (string, error) {
 	return user.Login, nil
 }
 
-func (c *PRClient) hasMyCommitInRange(pr PullRequest, since, until string) (bool, error) {
-	// キャッシュキーの生成
-	cacheKey := fmt.Sprintf("%s-%d-%s-%s", pr.Repository.FullName, pr.Number, since, until)
-
-	// キャッシュチェック
-	c.commitCacheMux.RLock()
-	if result, ok := c.commitCache[cacheKey]; ok {
-		c.debugPrint("コミットキャッシュヒット: %s = %v\n", cacheKey, result)
-		c.commitCacheMux.RUnlock()
-		return result, nil
-	}
-	c.commitCacheMux.RUnlock()
-
-	// 日付範囲の設定
-	var sinceTime, untilTime time.Time
-	var err1, err2 error
-	if since != "" {
-		sinceTime, err1 = time.Parse("2006-01-02", since)
-	} else {
-		sinceTime = timeNow().Truncate(24 * time.Hour)
-	}
-	if until != "" {
-		untilTime, err2 = time.Parse("2006-01-02", until)
-		untilTime = untilTime.Add(24 * time.Hour)
-	} else {
-		untilTime = timeNow().Add(24 * time.Hour)
-	}
-	if err1 != nil || err2 != nil {
-		return false, fmt.Errorf("日付の解析に失敗: %v, %v", err1, err2)
-	}
+// IsAuthor は指定したユーザーがPRの作成者かどうかを返す
+func (pr PullRequest) IsAuthor(login string) bool {
+	return pr.Author != "" && strings.EqualFold(pr.Author, login)
+}
 
-	// デバッグ出力
-	c.debugPrint("日付範囲: %s 〜 %s\n", sinceTime.Format("2006-01-02 15:04:05"), untilTime.Format("2006-01-02 15:04:05"))
+func buildSearchQuery(opts SearchOptions, role Role) string {
+	dateRange := opts.dateRange().qualifier()
 
-	// PRの作成者が自分の場合はtrueを返す
-	if pr.IsAuthor() {
-		c.debugPrint("PRの作成者が自分です\n")
-		c.commitCacheMux.Lock()
-		c.commitCache[cacheKey] = true
-		c.commitCacheMux.Unlock()
-		return true, nil
+	// 自分との関係でPRを検索（活動の有無は別途確認）
+	// draft:trueとdraft:falseの両方を含めるためにis:prのみを使用
+	query := fmt.Sprintf("is:pr %s %s", dateRange, role.qualifier(opts.User))
+	if opts.OpenOnly {
+		query = fmt.Sprintf("is:pr is:open %s", role.qualifier(opts.User))
 	}
 
-	// ユーザー名の取得（キャッシュ使用）
-	username, err := c.getUser()
-	if err != nil {
-		c.debugPrint("ユーザー情報取得エラー: %v\n", err)
-		return false, err
-	}
-	c.debugPrint("ユーザー名: %s\n", username)
-
-	// PRのコミット情報を取得
-	var commits []struct {
-		Commit struct {
-			Author struct {
-				Name  string    `json:"name"`
-				Email string    `json:"email"`
-				Date  time.Time `json:"date"`
-			} `json:"author"`
-			Committer struct {
-				Name  string    `json:"name"`
-				Email string    `json:"email"`
-				Date  time.Time `json:"date"`
-			} `json:"committer"`
-		} `json:"commit"`
-		Author struct {
-			Login string `json:"login"`
-		} `json:"author"`
-		Committer struct {
-			Login string `json:"login"`
-		} `json:"committer"`
-	}
-
-	commitPath := fmt.Sprintf("repos/%s/pulls/%d/commits", pr.Repository.FullName, pr.Number)
-	c.debugPrint("コミット取得: %s\n", commitPath)
-
-	err = c.client.Get(commitPath, &commits)
-	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			c.debugPrint("コミット取得スキップ（404）: %s\n", commitPath)
-			c.commitCacheMux.Lock()
-			c.commitCache[cacheKey] = false
-			c.commitCacheMux.Unlock()
-			return false, nil
-		}
-		c.debugPrint("コミット取得エラー: %v\n", err)
-		return false, err
-	}
-
-	// コミットを確認
-	for _, commit := range commits {
-		if (commit.Author.Login == username || commit.Committer.Login == username) &&
-			commit.Commit.Author.Date.After(sinceTime) &&
-			commit.Commit.Author.Date.Before(untilTime) {
-			c.debugPrint("自分のコミットが見つかりました: %s (%s)\n", commit.Author.Login, commit.Commit.Author.Date)
-			c.commitCacheMux.Lock()
-			c.commitCache[cacheKey] = true
-			c.commitCacheMux.Unlock()
-			return true, nil
-		}
+	if opts.Org != "" {
+		query += fmt.Sprintf(" org:%s", opts.Org)
 	}
-
-	c.debugPrint("自分のコミットは見つかりませんでした\n")
-	c.commitCacheMux.Lock()
-	c.commitCache[cacheKey] = false
-	c.commitCacheMux.Unlock()
-	return false, nil
-}
-
-func (pr PullRequest) IsAuthor() bool {
-	// 自分がPRの作者かどうかを確認
-	// Note: Search APIのauthor:@meで既にフィルタリングされているため、
-	// ここではtrueを返すだけで十分
-	return true
-}
-
-func buildSearchQuery(org, repo, since, until string) string {
-	var dateRange string
-	if since != "" && until != "" {
-		dateRange = fmt.Sprintf("updated:%s..%s", since, until)
-	} else if since != "" {
-		dateRange = fmt.Sprintf("updated:>=%s", since)
-	} else if until != "" {
-		dateRange = fmt.Sprintf("updated:<=%s", until)
-	} else {
-		dateRange = fmt.Sprintf("updated:%s", timeNow().Format("2006-01-02"))
+	for _, qualifier := range repoQualifiers(opts.Repos) {
+		query += " " + qualifier
 	}
-
-	// 作者が自分のPRを検索（コミットは別途確認）
-	// draft:trueとdraft:falseの両方を含めるためにis:prのみを使用
-	query := fmt.Sprintf("is:pr %s author:@me", dateRange)
-
-	if org != "" {
-		query += fmt.Sprintf(" org:%s", org)
+	for _, qualifier := range opts.Filters.qualifiers() {
+		query += " " + qualifier
 	}
-	if repo != "" {
-		query += fmt.Sprintf(" repo:%s", repo)
+	if q := strings.TrimSpace(opts.Query); q != "" {
+		query += " " + q
 	}
 
 	return query
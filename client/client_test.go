@@ -7,25 +7,33 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/cli/go-gh/pkg/api"
 )
 
 func TestBuildSearchQuery(t *testing.T) {
 	// 固定の日付を使用してテスト
-	fixedDate := "2024-02-04"
+	fixedDate := "2024-02-04T00:00:00Z..2024-02-04T23:59:59Z"
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
 	tests := []struct {
 		name     string
 		org      string
-		repo     string
+		repos    []string
 		since    string
 		until    string
+		loc      *time.Location
 		expected string
 	}{
 		{
 			name:     "基本的なクエリ",
 			org:      "",
-			repo:     "",
 			since:    "",
 			until:    "",
 			expected: "is:pr updated:" + fixedDate + " author:@me",
@@ -33,7 +41,6 @@ func TestBuildSearchQuery(t *testing.T) {
 		{
 			name:     "組織指定",
 			org:      "testorg",
-			repo:     "",
 			since:    "",
 			until:    "",
 			expected: "is:pr updated:" + fixedDate + " author:@me org:testorg",
@@ -41,26 +48,45 @@ func TestBuildSearchQuery(t *testing.T) {
 		{
 			name:     "リポジトリ指定",
 			org:      "",
-			repo:     "owner/repo",
+			repos:    []string{"owner/repo"},
 			since:    "",
 			until:    "",
 			expected: "is:pr updated:" + fixedDate + " author:@me repo:owner/repo",
 		},
+		{
+			name:     "複数のリポジトリとワイルドカード指定",
+			repos:    []string{"owner/repo", "myorg/service-*", "myorg/api-*", "*/dotfiles"},
+			expected: "is:pr updated:" + fixedDate + " author:@me repo:owner/repo user:myorg",
+		},
 		{
 			name:     "日付範囲指定",
 			org:      "",
-			repo:     "",
 			since:    "2024-01-01",
 			until:    "2024-01-31",
-			expected: "is:pr updated:2024-01-01..2024-01-31 author:@me",
+			expected: "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z author:@me",
+		},
+		{
+			name:     "開始日のみ指定",
+			since:    "2024-01-01",
+			expected: "is:pr updated:2024-01-01T00:00:00Z..2024-02-04T23:59:59Z author:@me",
+		},
+		{
+			name:     "終了日のみ指定",
+			until:    "2024-01-31",
+			expected: "is:pr updated:<=2024-01-31T23:59:59Z author:@me",
+		},
+		{
+			name:     "タイムゾーン指定",
+			loc:      tokyo,
+			expected: "is:pr updated:2024-02-04T00:00:00+09:00..2024-02-04T23:59:59+09:00 author:@me",
 		},
 		{
 			name:     "すべての条件指定",
 			org:      "testorg",
-			repo:     "owner/repo",
+			repos:    []string{"owner/repo"},
 			since:    "2024-01-01",
 			until:    "2024-01-31",
-			expected: "is:pr updated:2024-01-01..2024-01-31 author:@me org:testorg repo:owner/repo",
+			expected: "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z author:@me org:testorg repo:owner/repo",
 		},
 	}
 
@@ -71,7 +97,19 @@ func TestBuildSearchQuery(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildSearchQuery(tt.org, tt.repo, tt.since, tt.until)
+			loc := tt.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+			rng, err := ParseDateRange(tt.since, tt.until, loc)
+			if err != nil {
+				t.Fatalf("ParseDateRange() error = %v", err)
+			}
+			got := buildSearchQuery(SearchOptions{
+				Org:   tt.org,
+				Repos: tt.repos,
+				Range: rng,
+			}, RoleAuthor)
 			if got != tt.expected {
 				t.Errorf("buildSearchQuery() = %v, want %v", got, tt.expected)
 			}
@@ -79,46 +117,29 @@ func TestBuildSearchQuery(t *testing.T) {
 	}
 }
 
-func TestExtractRepoFullName(t *testing.T) {
+func TestPullRequest_IsAuthor(t *testing.T) {
 	tests := []struct {
 		name     string
-		apiURL   string
-		expected string
+		author   string
+		login    string
+		expected bool
 	}{
-		{
-			name:     "正常なURL",
-			apiURL:   "https://api.github.com/repos/owner/repo/issues/1",
-			expected: "owner/repo",
-		},
-		{
-			name:     "不正なURL",
-			apiURL:   "invalid-url",
-			expected: "",
-		},
-		{
-			name:     "空のURL",
-			apiURL:   "",
-			expected: "",
-		},
+		{name: "作成者が自分", author: "testuser", login: "testuser", expected: true},
+		{name: "大文字小文字の違い", author: "TestUser", login: "testuser", expected: true},
+		{name: "作成者が他人", author: "other", login: "testuser", expected: false},
+		{name: "作成者が不明", author: "", login: "testuser", expected: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := extractRepoFullName(tt.apiURL)
-			if got != tt.expected {
-				t.Errorf("extractRepoFullName() = %v, want %v", got, tt.expected)
+			pr := PullRequest{Author: tt.author}
+			if got := pr.IsAuthor(tt.login); got != tt.expected {
+				t.Errorf("IsAuthor() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
 }
 
-func TestPullRequest_IsAuthor(t *testing.T) {
-	pr := PullRequest{}
-	if !pr.IsAuthor() {
-		t.Error("IsAuthor() = false, want true")
-	}
-}
-
 // モックサーバーを作成するヘルパー関数
 func setupMockServer(t *testing.T, responses map[string]interface{}) (*httptest.Server, *PRClient) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -147,7 +168,7 @@ func setupMockServer(t *testing.T, responses map[string]interface{}) (*httptest.
 	client := &PRClient{
 		client:      &mockRESTClient{baseURL: server.URL, t: t},
 		debug:       true,
-		commitCache: make(map[string]bool),
+		commitCache: make(map[string][]prCommit),
 	}
 
 	return server, client
@@ -163,11 +184,19 @@ func (c *mockRESTClient) Do(method string, path string, body io.Reader, response
 	return c.DoWithContext(context.Background(), method, path, body, response)
 }
 
+func (c *mockRESTClient) url(path string) string {
+	// ページネーションのLinkヘッダーは絶対URLで返される
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return fmt.Sprintf("%s/%s", c.baseURL, path)
+}
+
 func (c *mockRESTClient) DoWithContext(ctx context.Context, method string, path string, body io.Reader, response interface{}) error {
 	if method != "GET" {
 		return fmt.Errorf("method %s not implemented", method)
 	}
-	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.baseURL, path), body)
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), body)
 	if err != nil {
 		return err
 	}
@@ -177,6 +206,9 @@ func (c *mockRESTClient) DoWithContext(ctx context.Context, method string, path
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode > 299 {
+		return api.HandleHTTPError(resp)
+	}
 	return json.NewDecoder(resp.Body).Decode(response)
 }
 
@@ -205,21 +237,34 @@ func (c *mockRESTClient) Request(method string, path string, body io.Reader) (*h
 }
 
 func (c *mockRESTClient) RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.baseURL, path), body)
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	return http.DefaultClient.Do(req)
+	// go-ghと同じく2xx以外はエラーにする
+	if resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, api.HandleHTTPError(resp)
+	}
+	return resp, nil
 }
 
 func TestPRClient_FetchTodaysPRs(t *testing.T) {
 	// 固定の日付を使用してテスト
-	fixedDate := "2024-02-04"
 	now := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
 	timeNow = func() time.Time { return now }
 	defer func() { timeNow = time.Now }()
 
-	searchPath := fmt.Sprintf("/search/issues?order=desc&per_page=100&q=is%%3Apr+updated%%3A%s+author%%3A%%40me&sort=updated", fixedDate)
+	searchPath := "/search/issues?" + url.Values{
+		"q":        []string{"is:pr updated:2024-02-04T00:00:00Z..2024-02-04T23:59:59Z author:@me"},
+		"sort":     []string{"updated"},
+		"order":    []string{"desc"},
+		"per_page": []string{"100"},
+	}.Encode()
 
 	// モックレスポンスの準備
 	searchResponse := struct {
@@ -275,9 +320,17 @@ func TestPRClient_FetchTodaysPRs(t *testing.T) {
 	}
 
 	prResponse := struct {
-		Merged bool `json:"merged"`
+		Merged       bool `json:"merged"`
+		Additions    int  `json:"additions"`
+		Deletions    int  `json:"deletions"`
+		ChangedFiles int  `json:"changed_files"`
+		Commits      int  `json:"commits"`
 	}{
-		Merged: true,
+		Merged:       true,
+		Additions:    120,
+		Deletions:    30,
+		ChangedFiles: 4,
+		Commits:      2,
 	}
 
 	userResponse := struct {
@@ -287,16 +340,17 @@ func TestPRClient_FetchTodaysPRs(t *testing.T) {
 	}
 
 	responses := map[string]interface{}{
-		searchPath:                          searchResponse,
-		"/repos/owner/repo/pulls/1":         prResponse,
-		"/user":                             userResponse,
-		"/repos/owner/repo/pulls/1/commits": []struct{}{},
+		searchPath:                  searchResponse,
+		"/repos/owner/repo/pulls/1": prResponse,
+		"/user":                     userResponse,
+		"/repos/owner/repo/pulls/1/commits?per_page=100": []struct{}{},
+		"/repos/owner/repo/issues/1/events?per_page=100": []struct{}{},
 	}
 
 	server, client := setupMockServer(t, responses)
 	defer server.Close()
 
-	prs, err := client.FetchTodaysPRs("", "", "", "")
+	prs, err := client.FetchTodaysPRs(SearchOptions{Range: Today(time.UTC)})
 	if err != nil {
 		t.Fatalf("FetchTodaysPRs() error = %v", err)
 	}
@@ -315,7 +369,18 @@ func TestPRClient_FetchTodaysPRs(t *testing.T) {
 	if pr.Draft {
 		t.Errorf("PR.Draft = true, want false")
 	}
+	if pr.Additions != 120 || pr.Deletions != 30 || pr.ChangedFiles != 4 || pr.Commits != 2 {
+		t.Errorf("PR diff stats = +%d -%d, %d files, %d commits, want +120 -30, 4 files, 2 commits",
+			pr.Additions, pr.Deletions, pr.ChangedFiles, pr.Commits)
+	}
 	if pr.Repository.FullName != "owner/repo" {
 		t.Errorf("PR.Repository.FullName = %v, want owner/repo", pr.Repository.FullName)
 	}
+	if len(pr.Roles) != 1 || pr.Roles[0] != RoleAuthor {
+		t.Errorf("PR.Roles = %v, want [author]", pr.Roles)
+	}
+	// 自分のコミットもマージもないため受動的な更新と判定される
+	if pr.Contribution != ContributionPassive {
+		t.Errorf("PR.Contribution = %v, want passive", pr.Contribution)
+	}
 }
@@ -0,0 +1,313 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Contribution は期間内にPRに対して自分が行ったことの分類
+type Contribution string
+
+const (
+	// ContributionMerged は期間内にマージした（自分のPRがマージされた場合を含む）
+	ContributionMerged Contribution = "merged"
+	// ContributionReadyForReview は期間内にドラフトからレビュー可能にした
+	ContributionReadyForReview Contribution = "ready_for_review"
+	// ContributionCommitted は期間内にコミットをプッシュした
+	ContributionCommitted Contribution = "committed"
+	// ContributionCreated は期間内にPRを作成した
+	ContributionCreated Contribution = "created"
+	// ContributionReviewed は期間内にレビューした
+	ContributionReviewed Contribution = "reviewed"
+	// ContributionCommented は期間内にコメントした
+	ContributionCommented Contribution = "commented"
+	// ContributionPassive は他の人のコメントなどで更新されただけ
+	ContributionPassive Contribution = "passive"
+)
+
+// 関わり方の優先順位。複数の活動がある場合は先に現れるものを代表とする
+var contributionPriority = []Contribution{
+	ContributionMerged,
+	ContributionReadyForReview,
+	ContributionCommitted,
+	ContributionCreated,
+	ContributionReviewed,
+	ContributionCommented,
+}
+
+// Activity は期間内に自分が行った個々の活動
+type Activity struct {
+	Kind Contribution `json:"kind"`
+	At   time.Time    `json:"at"`
+}
+
+// prCommit はPRに含まれるコミットのうち判定に必要な情報
+type prCommit struct {
+	AuthorLogin    string
+	CommitterLogin string
+	AuthoredAt     time.Time
+}
+
+// prEvent はPRのイベントのうち判定に必要な情報
+type prEvent struct {
+	Event      string
+	ActorLogin string
+	CreatedAt  time.Time
+}
+
+// prDetails はPRの関わり方の判定に使う取得済みの情報
+type prDetails struct {
+	mergedAt *time.Time
+	mergedBy string
+	commits  []prCommit
+	events   []prEvent
+	reviews  []prReview
+	comments []prComment
+}
+
+// IsPassive は期間内に自分の活動がなかったかどうかを返す
+func (pr PullRequest) IsPassive() bool {
+	return pr.Contribution == ContributionPassive
+}
+
+// detectContribution はREST APIで必要な情報を取得し、usernameのユーザーのPRとの関わり方を判定する。
+// withReviewsが指定された場合はレビューとコメントも集計する。
+func (c *PRClient) detectContribution(pr *PullRequest, rng DateRange, withReviews bool, username string) error {
+	// デバッグ出力
+	c.debugPrint("日付範囲: %s 〜 %s\n", rng.Since.Format(time.RFC3339), rng.Until.Format(time.RFC3339))
+	c.debugPrint("ユーザー名: %s\n", username)
+
+	// 変更量とマージ情報を取得
+	var details prDetails
+	prPath := fmt.Sprintf("repos/%s/pulls/%d", pr.Repository.FullName, pr.Number)
+	c.debugPrint("PR詳細取得: %s\n", prPath)
+
+	var prDetail struct {
+		pullStatus
+		Merged   bool       `json:"merged"`
+		MergedAt *time.Time `json:"merged_at"`
+		MergedBy *struct {
+			Login string `json:"login"`
+		} `json:"merged_by"`
+		Additions    int `json:"additions"`
+		Deletions    int `json:"deletions"`
+		ChangedFiles int `json:"changed_files"`
+		Commits      int `json:"commits"`
+		Base         struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	}
+	// 取得できないまま返すとマージ状態や変更量、ベースブランチが空のPRになるため失敗として扱う
+	if err := c.client.Get(prPath, &prDetail); err != nil {
+		c.debugPrint("PR詳細の取得に失敗: %v\n", err)
+		return fmt.Errorf("PRの詳細の取得に失敗: %w", err)
+	}
+	pr.Additions = prDetail.Additions
+	pr.Deletions = prDetail.Deletions
+	pr.ChangedFiles = prDetail.ChangedFiles
+	pr.Commits = prDetail.Commits
+	pr.Base = prDetail.Base.Ref
+	// CIの状態とレビュー状況の取得で同じPRの詳細を取得し直さないようにする
+	if pr.State == "open" {
+		status := prDetail.pullStatus
+		pr.status = &status
+	}
+	// オープンなPRはマージされていない
+	if pr.State == "closed" {
+		pr.Merged = prDetail.Merged
+		details.mergedAt = prDetail.MergedAt
+		if prDetail.MergedBy != nil {
+			details.mergedBy = prDetail.MergedBy.Login
+		}
+		c.debugPrint("マージ状態: %v\n", pr.Merged)
+	}
+
+	var err error
+	details.commits, err = c.fetchCommits(pr.Repository.FullName, pr.Number)
+	if err != nil {
+		return err
+	}
+
+	// ドラフトのPRも期間内にレビュー可能にされてからドラフトに戻された可能性があるため、常に取得する
+	details.events, err = c.fetchEvents(pr.Repository.FullName, pr.Number)
+	if err != nil {
+		return err
+	}
+
+	if withReviews {
+		details.reviews, err = c.fetchReviews(pr.Repository.FullName, pr.Number)
+		if err != nil {
+			return err
+		}
+		if pr.status != nil {
+			pr.status.reviews = details.reviews
+			pr.status.hasReviews = true
+		}
+		details.comments, err = c.fetchComments(pr.Repository.FullName, pr.Number)
+		if err != nil {
+			return err
+		}
+		pr.ReviewState, pr.CommentCount = summarizeReviews(details, username, rng)
+	}
+
+	pr.Activities = collectActivities(*pr, username, rng, details)
+	pr.Contribution = primaryContribution(pr.Activities)
+	c.debugPrint("関わり方: %s\n", pr.Contribution)
+	return nil
+}
+
+// fetchCommits はPRのコミット一覧を取得する（キャッシュ使用）
+func (c *PRClient) fetchCommits(repoFullName string, number int) ([]prCommit, error) {
+	cacheKey := fmt.Sprintf("%s-%d", repoFullName, number)
+
+	// キャッシュチェック
+	c.commitCacheMux.RLock()
+	if commits, ok := c.commitCache[cacheKey]; ok {
+		c.debugPrint("コミットキャッシュヒット: %s\n", cacheKey)
+		c.commitCacheMux.RUnlock()
+		return commits, nil
+	}
+	c.commitCacheMux.RUnlock()
+
+	// PRのコミット情報を取得
+	type commitResponse struct {
+		Commit struct {
+			Author struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		Committer struct {
+			Login string `json:"login"`
+		} `json:"committer"`
+	}
+
+	// 既定の30件では期間内のコミットが2ページ目以降に入るため全ページ取得する
+	commitPath := fmt.Sprintf("repos/%s/pulls/%d/commits?per_page=100", repoFullName, number)
+	c.debugPrint("コミット取得: %s\n", commitPath)
+
+	response, err := fetchAllPages[commitResponse](c, commitPath)
+	if err != nil {
+		if !strings.Contains(err.Error(), "404") {
+			c.debugPrint("コミット取得エラー: %v\n", err)
+			return nil, fmt.Errorf("コミット情報の取得に失敗: %w", err)
+		}
+		c.debugPrint("コミット取得スキップ（404）: %s\n", commitPath)
+	}
+	var commits []prCommit
+	for _, commit := range response {
+		commits = append(commits, prCommit{
+			AuthorLogin:    commit.Author.Login,
+			CommitterLogin: commit.Committer.Login,
+			AuthoredAt:     commit.Commit.Author.Date,
+		})
+	}
+
+	c.commitCacheMux.Lock()
+	c.commitCache[cacheKey] = commits
+	c.commitCacheMux.Unlock()
+	return commits, nil
+}
+
+// fetchEvents はPRのイベント一覧を取得する
+func (c *PRClient) fetchEvents(repoFullName string, number int) ([]prEvent, error) {
+	type eventResponse struct {
+		Event string `json:"event"`
+		Actor struct {
+			Login string `json:"login"`
+		} `json:"actor"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	eventsPath := fmt.Sprintf("repos/%s/issues/%d/events?per_page=100", repoFullName, number)
+	c.debugPrint("イベント取得: %s\n", eventsPath)
+
+	response, err := fetchAllPages[eventResponse](c, eventsPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			c.debugPrint("イベント取得スキップ（404）: %s\n", eventsPath)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("イベント情報の取得に失敗: %w", err)
+	}
+
+	events := make([]prEvent, 0, len(response))
+	for _, event := range response {
+		events = append(events, prEvent{
+			Event:      event.Event,
+			ActorLogin: event.Actor.Login,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return events, nil
+}
+
+// collectActivities は取得済みの情報から期間内の自分の活動を集める
+func collectActivities(pr PullRequest, username string, rng DateRange, details prDetails) []Activity {
+	inRange := rng.Contains
+
+	var activities []Activity
+	add := func(kind Contribution, at time.Time) {
+		// 日の境界は期間のタイムゾーンに合わせる
+		at = at.In(rng.Location())
+		// 同じ種類の活動は日ごとに1件にまとめる
+		for _, a := range activities {
+			if a.Kind == kind && a.At.Format("2006-01-02") == at.Format("2006-01-02") {
+				return
+			}
+		}
+		activities = append(activities, Activity{Kind: kind, At: at})
+	}
+
+	if pr.IsAuthor(username) && inRange(pr.CreatedAt) {
+		add(ContributionCreated, pr.CreatedAt)
+	}
+	for _, commit := range details.commits {
+		if (strings.EqualFold(commit.AuthorLogin, username) || strings.EqualFold(commit.CommitterLogin, username)) &&
+			inRange(commit.AuthoredAt) {
+			add(ContributionCommitted, commit.AuthoredAt)
+		}
+	}
+	for _, event := range details.events {
+		if event.Event == "ready_for_review" && strings.EqualFold(event.ActorLogin, username) && inRange(event.CreatedAt) {
+			add(ContributionReadyForReview, event.CreatedAt)
+		}
+	}
+	if pr.Merged && details.mergedAt != nil && inRange(*details.mergedAt) &&
+		(strings.EqualFold(details.mergedBy, username) || pr.IsAuthor(username)) {
+		add(ContributionMerged, *details.mergedAt)
+	}
+	for _, review := range details.reviews {
+		if strings.EqualFold(review.AuthorLogin, username) && review.State != "PENDING" && inRange(review.SubmittedAt) {
+			add(ContributionReviewed, review.SubmittedAt)
+		}
+	}
+	for _, comment := range details.comments {
+		if strings.EqualFold(comment.AuthorLogin, username) && inRange(comment.CreatedAt) {
+			add(ContributionCommented, comment.CreatedAt)
+		}
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].At.Before(activities[j].At)
+	})
+	return activities
+}
+
+// primaryContribution は活動の中で最も重要なものを代表として返す
+func primaryContribution(activities []Activity) Contribution {
+	for _, kind := range contributionPriority {
+		for _, a := range activities {
+			if a.Kind == kind {
+				return kind
+			}
+		}
+	}
+	return ContributionPassive
+}
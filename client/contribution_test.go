@@ -0,0 +1,192 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCollectActivities(t *testing.T) {
+	sinceTime := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
+	untilTime := sinceTime.Add(24 * time.Hour)
+	inRange := sinceTime.Add(3 * time.Hour)
+	before := sinceTime.Add(-3 * time.Hour)
+
+	tests := []struct {
+		name       string
+		pr         PullRequest
+		details    prDetails
+		kinds      []Contribution
+		contribute Contribution
+	}{
+		{
+			name:       "期間内に作成",
+			pr:         PullRequest{Author: "me", CreatedAt: inRange},
+			kinds:      []Contribution{ContributionCreated},
+			contribute: ContributionCreated,
+		},
+		{
+			name: "期間内にコミット",
+			pr:   PullRequest{Author: "me", CreatedAt: before},
+			details: prDetails{commits: []prCommit{
+				{AuthorLogin: "me", AuthoredAt: inRange},
+				{AuthorLogin: "me", AuthoredAt: inRange.Add(time.Hour)},
+				{AuthorLogin: "other", AuthoredAt: inRange},
+			}},
+			kinds:      []Contribution{ContributionCommitted},
+			contribute: ContributionCommitted,
+		},
+		{
+			name: "期間内にレビュー可能にした",
+			pr:   PullRequest{Author: "me", CreatedAt: inRange},
+			details: prDetails{events: []prEvent{
+				{Event: "ready_for_review", ActorLogin: "me", CreatedAt: inRange.Add(time.Hour)},
+				{Event: "labeled", ActorLogin: "me", CreatedAt: inRange},
+			}},
+			kinds:      []Contribution{ContributionCreated, ContributionReadyForReview},
+			contribute: ContributionReadyForReview,
+		},
+		{
+			name:       "他の人が自分のPRをマージ",
+			pr:         PullRequest{Author: "me", CreatedAt: before, Merged: true},
+			details:    prDetails{mergedAt: &inRange, mergedBy: "other"},
+			kinds:      []Contribution{ContributionMerged},
+			contribute: ContributionMerged,
+		},
+		{
+			name:       "期間外のマージ",
+			pr:         PullRequest{Author: "me", CreatedAt: before, Merged: true},
+			details:    prDetails{mergedAt: &before, mergedBy: "me"},
+			contribute: ContributionPassive,
+		},
+		{
+			name: "他の人のPRをレビューしてコメント",
+			pr:   PullRequest{Author: "other", CreatedAt: inRange},
+			details: prDetails{
+				reviews:  []prReview{{AuthorLogin: "me", State: "APPROVED", SubmittedAt: inRange.Add(time.Hour)}},
+				comments: []prComment{{AuthorLogin: "me", CreatedAt: inRange}},
+			},
+			kinds:      []Contribution{ContributionCommented, ContributionReviewed},
+			contribute: ContributionReviewed,
+		},
+		{
+			name: "他の人の活動のみ",
+			pr:   PullRequest{Author: "me", CreatedAt: before},
+			details: prDetails{
+				commits: []prCommit{
+					{AuthorLogin: "other", AuthoredAt: inRange},
+				},
+				events: []prEvent{
+					{Event: "ready_for_review", ActorLogin: "other", CreatedAt: inRange},
+				},
+			},
+			contribute: ContributionPassive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activities := collectActivities(tt.pr, "me", DateRange{Since: sinceTime, Until: untilTime}, tt.details)
+			if len(activities) != len(tt.kinds) {
+				t.Fatalf("collectActivities() = %v, want kinds %v", activities, tt.kinds)
+			}
+			for i, a := range activities {
+				if a.Kind != tt.kinds[i] {
+					t.Errorf("activities[%d].Kind = %v, want %v", i, a.Kind, tt.kinds[i])
+				}
+			}
+			if got := primaryContribution(activities); got != tt.contribute {
+				t.Errorf("primaryContribution() = %v, want %v", got, tt.contribute)
+			}
+		})
+	}
+}
+
+func TestPRClient_detectContribution_Pagination(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		page := r.URL.Query().Get("page")
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/1":
+			fmt.Fprint(w, `{}`)
+		case "/repos/owner/repo/pulls/1/commits":
+			if got := r.URL.Query().Get("per_page"); got != "100" {
+				t.Errorf("per_page = %q, want 100", got)
+			}
+			if page == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls/1/commits?per_page=100&page=2>; rel="next"`, serverURL))
+				fmt.Fprint(w, `[{"author":{"login":"me"},"commit":{"author":{"date":"2024-01-01T00:00:00Z"}}}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"author":{"login":"me"},"commit":{"author":{"date":"2024-02-04T03:00:00Z"}}}]`)
+		case "/repos/owner/repo/issues/1/events":
+			if page == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/issues/1/events?per_page=100&page=2>; rel="next"`, serverURL))
+				fmt.Fprint(w, `[{"event":"labeled","actor":{"login":"me"},"created_at":"2024-01-01T00:00:00Z"}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"event":"ready_for_review","actor":{"login":"me"},"created_at":"2024-02-04T04:00:00Z"}]`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	pr := PullRequest{
+		Number:    1,
+		Author:    "other",
+		State:     "open",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	pr.Repository.FullName = "owner/repo"
+	if err := client.detectContribution(&pr, utcRange(t, "2024-02-04", "2024-02-04"), false, "me"); err != nil {
+		t.Fatalf("detectContribution() error = %v", err)
+	}
+
+	// 2ページ目にある期間内のコミットとイベントも判定に使う
+	want := []Contribution{ContributionCommitted, ContributionReadyForReview}
+	if len(pr.Activities) != len(want) {
+		t.Fatalf("Activities = %v, want kinds %v", pr.Activities, want)
+	}
+	for i, a := range pr.Activities {
+		if a.Kind != want[i] {
+			t.Errorf("Activities[%d].Kind = %v, want %v", i, a.Kind, want[i])
+		}
+	}
+	if pr.Contribution != ContributionReadyForReview {
+		t.Errorf("Contribution = %v, want %v", pr.Contribution, ContributionReadyForReview)
+	}
+}
+
+func TestPRClient_detectContribution_Draft(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/1":
+			fmt.Fprint(w, `{}`)
+		case "/repos/owner/repo/pulls/1/commits":
+			fmt.Fprint(w, `[]`)
+		case "/repos/owner/repo/issues/1/events":
+			fmt.Fprint(w, `[{"event":"ready_for_review","actor":{"login":"me"},"created_at":"2024-02-04T04:00:00Z"},`+
+				`{"event":"convert_to_draft","actor":{"login":"me"},"created_at":"2024-02-04T05:00:00Z"}]`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	// 期間内にレビュー可能にしてからドラフトに戻したPR
+	pr := PullRequest{
+		Number:    1,
+		Author:    "other",
+		State:     "open",
+		Draft:     true,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	pr.Repository.FullName = "owner/repo"
+	if err := client.detectContribution(&pr, utcRange(t, "2024-02-04", "2024-02-04"), false, "me"); err != nil {
+		t.Fatalf("detectContribution() error = %v", err)
+	}
+	if pr.Contribution != ContributionReadyForReview {
+		t.Errorf("Contribution = %v, want %v", pr.Contribution, ContributionReadyForReview)
+	}
+}
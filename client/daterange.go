@@ -0,0 +1,168 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateRange は検索と活動の判定に共通して使う期間。
+// Sinceを含みUntilを含まない。Sinceがゼロ値の場合は下限なしとする。
+type DateRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+// ParseDateRange は--since/--untilの指定から、指定したタイムゾーンの
+// 日の境界に合わせた期間を求める。省略された場合は今日とする。
+//
+// 日付はYYYY-MM-DD形式のほか、today、yesterday、this-week、last-week、
+// this-month、last-month、last-business-day、7d（今日を含む直近7日間）、
+// 2026-W41（ISO週）を指定できる。sinceは指定した期間の始まり、
+// untilは指定した期間の終わりとして扱う。
+func ParseDateRange(since, until string, loc *time.Location) (DateRange, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	// 相対的な指定はすべて同じ時刻を基準に解決する
+	today := startOfDay(timeNow().In(loc))
+
+	var r DateRange
+	switch {
+	case since == "" && until == "":
+		r.Since = today
+	case since != "":
+		start, _, err := resolveDateExpr(since, today)
+		if err != nil {
+			return DateRange{}, err
+		}
+		r.Since = start
+	}
+
+	r.Until = today.AddDate(0, 0, 1)
+	if until != "" {
+		_, end, err := resolveDateExpr(until, today)
+		if err != nil {
+			return DateRange{}, err
+		}
+		r.Until = end
+	}
+
+	if !r.Since.IsZero() && !r.Since.Before(r.Until) {
+		return DateRange{}, fmt.Errorf("期間の指定が不正です: %s 〜 %s", since, until)
+	}
+	return r, nil
+}
+
+var (
+	relativeDaysRE = regexp.MustCompile(`^(\d+)d$`)
+	isoWeekRE      = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+)
+
+// resolveDateExpr は日付の指定を、todayを基準にした期間[start, end)に解決する
+func resolveDateExpr(expr string, today time.Time) (time.Time, time.Time, error) {
+	// 週の始まりは月曜日とする
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+
+	switch strings.ToLower(expr) {
+	case "today":
+		return today, today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, nil
+	case "this-week":
+		return monday, monday.AddDate(0, 0, 7), nil
+	case "last-week":
+		return monday.AddDate(0, 0, -7), monday, nil
+	case "this-month":
+		return firstOfMonth, firstOfMonth.AddDate(0, 1, 0), nil
+	case "last-month":
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth, nil
+	case "last-business-day":
+		day := previousBusinessDay(today)
+		return day, day.AddDate(0, 0, 1), nil
+	}
+
+	if m := relativeDaysRE.FindStringSubmatch(expr); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil || days < 1 {
+			return time.Time{}, time.Time{}, fmt.Errorf("日数の指定が不正です: %s", expr)
+		}
+		return today.AddDate(0, 0, -(days - 1)), today.AddDate(0, 0, 1), nil
+	}
+
+	if m := isoWeekRE.FindStringSubmatch(strings.ToUpper(expr)); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		// 1月4日を含む週がその年の第1週
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, today.Location())
+		start := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+		if _, w := start.ISOWeek(); week < 1 || w != week {
+			return time.Time{}, time.Time{}, fmt.Errorf("週の指定が不正です: %s", expr)
+		}
+		return start, start.AddDate(0, 0, 7), nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", expr, today.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("日付の解析に失敗: %s（YYYY-MM-DD、yesterday、last-week、7d、2026-W41などを指定してください）", expr)
+	}
+	return t, t.AddDate(0, 0, 1), nil
+}
+
+// previousBusinessDay は前営業日（土日を除く）の始まりを返す
+func previousBusinessDay(t time.Time) time.Time {
+	day := startOfDay(t).AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// Today は指定したタイムゾーンでの今日の期間を返す
+func Today(loc *time.Location) DateRange {
+	r, _ := ParseDateRange("", "", loc)
+	return r
+}
+
+// Location は期間の境界に使われているタイムゾーンを返す
+func (r DateRange) Location() *time.Location {
+	return r.Until.Location()
+}
+
+// LastDay は期間の最終日を返す
+func (r DateRange) LastDay() time.Time {
+	return r.Until.Add(-time.Nanosecond)
+}
+
+// Contains は指定した時刻が期間内かどうかを返す
+func (r DateRange) Contains(t time.Time) bool {
+	return (r.Since.IsZero() || !t.Before(r.Since)) && t.Before(r.Until)
+}
+
+// qualifier は検索クエリで使用するupdated修飾子を返す。
+// タイムゾーンを反映させるためにオフセット付きの日時で指定する。
+func (r DateRange) qualifier() string {
+	// 範囲指定は終端を含むため、1秒前を終端とする
+	last := r.Until.Add(-time.Second).Format(time.RFC3339)
+	if r.Since.IsZero() {
+		return fmt.Sprintf("updated:<=%s", last)
+	}
+	return fmt.Sprintf("updated:%s..%s", r.Since.Format(time.RFC3339), last)
+}
+
+// split は検索結果が上限を超えた場合に期間を二分割する。
+// 下限がない場合や、これ以上分割できない場合はfalseを返す。
+func (r DateRange) split() (DateRange, DateRange, bool) {
+	if r.Since.IsZero() || r.Until.Sub(r.Since) < 2*time.Second {
+		return DateRange{}, DateRange{}, false
+	}
+	mid := r.Since.Add(r.Until.Sub(r.Since) / 2).Truncate(time.Second)
+	return DateRange{Since: r.Since, Until: mid}, DateRange{Since: mid, Until: r.Until}, true
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateRange(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// UTCでは2月3日だが、日本時間では2月4日の朝
+	now := time.Date(2024, 2, 3, 22, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		name    string
+		since   string
+		until   string
+		loc     *time.Location
+		want    DateRange
+		wantErr bool
+	}{
+		{
+			name: "UTCの今日",
+			loc:  time.UTC,
+			want: DateRange{
+				Since: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "日本時間の今日",
+			loc:  tokyo,
+			want: DateRange{
+				Since: time.Date(2024, 2, 4, 0, 0, 0, 0, tokyo),
+				Until: time.Date(2024, 2, 5, 0, 0, 0, 0, tokyo),
+			},
+		},
+		{
+			name:  "日本時間の期間指定",
+			since: "2024-01-01",
+			until: "2024-01-31",
+			loc:   tokyo,
+			want: DateRange{
+				Since: time.Date(2024, 1, 1, 0, 0, 0, 0, tokyo),
+				Until: time.Date(2024, 2, 1, 0, 0, 0, 0, tokyo),
+			},
+		},
+		{
+			name:  "終了日のみ",
+			until: "2024-01-31",
+			loc:   time.UTC,
+			want: DateRange{
+				Until: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "先週のみ",
+			since: "last-week",
+			until: "last-week",
+			loc:   tokyo,
+			want: DateRange{
+				Since: time.Date(2024, 1, 22, 0, 0, 0, 0, tokyo),
+				Until: time.Date(2024, 1, 29, 0, 0, 0, 0, tokyo),
+			},
+		},
+		{
+			name:  "直近7日間",
+			since: "7d",
+			loc:   tokyo,
+			want: DateRange{
+				Since: time.Date(2024, 1, 29, 0, 0, 0, 0, tokyo),
+				Until: time.Date(2024, 2, 5, 0, 0, 0, 0, tokyo),
+			},
+		},
+		{
+			name:    "不正な日付",
+			since:   "2024/01/01",
+			loc:     time.UTC,
+			wantErr: true,
+		},
+		{
+			name:    "開始日が終了日より後",
+			since:   "2024-01-31",
+			until:   "2024-01-01",
+			loc:     time.UTC,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateRange(tt.since, tt.until, tt.loc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Since.Equal(tt.want.Since) || !got.Until.Equal(tt.want.Until) {
+				t.Errorf("ParseDateRange() = %v..%v, want %v..%v", got.Since, got.Until, tt.want.Since, tt.want.Until)
+			}
+		})
+	}
+}
+
+func TestDateRange_Contains(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := DateRange{
+		Since: time.Date(2024, 2, 4, 0, 0, 0, 0, tokyo),
+		Until: time.Date(2024, 2, 5, 0, 0, 0, 0, tokyo),
+	}
+
+	// 日本時間の早朝のコミットはUTCでは前日になる
+	if !rng.Contains(time.Date(2024, 2, 3, 16, 30, 0, 0, time.UTC)) {
+		t.Error("Contains(2024-02-04 01:30 JST) = false, want true")
+	}
+	if rng.Contains(time.Date(2024, 2, 3, 14, 59, 59, 0, time.UTC)) {
+		t.Error("Contains(2024-02-03 23:59:59 JST) = true, want false")
+	}
+	if rng.Contains(rng.Until) {
+		t.Error("Contains(Until) = true, want false")
+	}
+}
+
+func TestDateRange_split(t *testing.T) {
+	rng := DateRange{
+		Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	first, second, ok := rng.split()
+	if !ok {
+		t.Fatal("split() ok = false, want true")
+	}
+	mid := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	if !first.Since.Equal(rng.Since) || !first.Until.Equal(mid) || !second.Since.Equal(mid) || !second.Until.Equal(rng.Until) {
+		t.Errorf("split() = %v..%v, %v..%v", first.Since, first.Until, second.Since, second.Until)
+	}
+
+	if _, _, ok := (DateRange{Until: rng.Until}).split(); ok {
+		t.Error("split() without Since ok = true, want false")
+	}
+}
+
+func TestResolveDateExpr(t *testing.T) {
+	// 2026-10-16は金曜日
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expr      string
+		today     time.Time
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{name: "日付", expr: "2026-10-01", today: today, wantStart: "2026-10-01", wantEnd: "2026-10-02"},
+		{name: "今日", expr: "today", today: today, wantStart: "2026-10-16", wantEnd: "2026-10-17"},
+		{name: "昨日", expr: "yesterday", today: today, wantStart: "2026-10-15", wantEnd: "2026-10-16"},
+		{name: "今週", expr: "this-week", today: today, wantStart: "2026-10-12", wantEnd: "2026-10-19"},
+		{name: "先週", expr: "last-week", today: today, wantStart: "2026-10-05", wantEnd: "2026-10-12"},
+		{name: "日曜日の今週", expr: "this-week", today: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), wantStart: "2026-10-12", wantEnd: "2026-10-19"},
+		{name: "今月", expr: "this-month", today: today, wantStart: "2026-10-01", wantEnd: "2026-11-01"},
+		{name: "先月", expr: "last-month", today: today, wantStart: "2026-09-01", wantEnd: "2026-10-01"},
+		{name: "直近7日間", expr: "7d", today: today, wantStart: "2026-10-10", wantEnd: "2026-10-17"},
+		{name: "月曜日の前営業日は金曜日", expr: "last-business-day", today: monday, wantStart: "2026-10-16", wantEnd: "2026-10-17"},
+		{name: "ISO週", expr: "2026-W41", today: today, wantStart: "2026-10-05", wantEnd: "2026-10-12"},
+		{name: "年をまたぐISO週", expr: "2026-W01", today: today, wantStart: "2025-12-29", wantEnd: "2026-01-05"},
+		{name: "存在しないISO週", expr: "2026-W54", today: today, wantErr: true},
+		{name: "0日間", expr: "0d", today: today, wantErr: true},
+		{name: "不明な指定", expr: "tomorrow", today: today, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := resolveDateExpr(tt.expr, tt.today)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveDateExpr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := start.Format("2006-01-02"); got != tt.wantStart {
+				t.Errorf("resolveDateExpr() start = %v, want %v", got, tt.wantStart)
+			}
+			if got := end.Format("2006-01-02"); got != tt.wantEnd {
+				t.Errorf("resolveDateExpr() end = %v, want %v", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestPreviousBusinessDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected string
+	}{
+		{name: "月曜日は金曜日", now: time.Date(2024, 2, 5, 9, 0, 0, 0, time.UTC), expected: "2024-02-02"},
+		{name: "火曜日は月曜日", now: time.Date(2024, 2, 6, 9, 0, 0, 0, time.UTC), expected: "2024-02-05"},
+		{name: "日曜日は金曜日", now: time.Date(2024, 2, 4, 9, 0, 0, 0, time.UTC), expected: "2024-02-02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := previousBusinessDay(tt.now).Format("2006-01-02"); got != tt.expected {
+				t.Errorf("previousBusinessDay() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
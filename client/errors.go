@@ -0,0 +1,85 @@
+package client
+
+import "fmt"
+
+// PRError は詳細情報の取得に失敗したPRと理由
+type PRError struct {
+	// 取得に失敗したPR（検索自体の一部が失敗した場合は空）
+	Repository string `json:"repository,omitempty"`
+	Number     int    `json:"number,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Message    string `json:"message"`
+
+	err error
+}
+
+func (e PRError) Error() string {
+	if e.Repository == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s#%d: %s", e.Repository, e.Number, e.Message)
+}
+
+func (e PRError) Unwrap() error {
+	return e.err
+}
+
+func newPRError(repository string, number int, url string, err error) PRError {
+	return PRError{
+		Repository: repository,
+		Number:     number,
+		URL:        url,
+		Message:    err.Error(),
+		err:        err,
+	}
+}
+
+// SetStrict はPRの取得に1件でも失敗したら全体を失敗させるかを設定する
+func (c *PRClient) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+// Errors は取得に失敗したPRの一覧を返す（SetStrict(true)の場合は常に空）
+func (c *PRClient) Errors() []PRError {
+	c.warningsMux.Lock()
+	defer c.warningsMux.Unlock()
+	return append([]PRError(nil), c.failures...)
+}
+
+// RecordFailure はPR単位ではない取得の失敗（複数のホストのうち1つのホストでの失敗など）を記録する。
+// strictの場合は記録せずにそのままエラーを返す
+func (c *PRClient) RecordFailure(err error) error {
+	return c.collectFailures([]PRError{newPRError("", 0, "", err)})
+}
+
+// collectFailures は取得に失敗したPRを記録する。strictの場合は最初の失敗を返す。
+// standupのように同じクライアントで複数回取得すると同じPRが何度も失敗するため、
+// 記録済みのPR（URLがない場合は同じメッセージ）は重複して記録しない。
+func (c *PRClient) collectFailures(failures []PRError) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	if c.strict {
+		return failures[0]
+	}
+	c.warningsMux.Lock()
+	defer c.warningsMux.Unlock()
+	for _, failure := range failures {
+		if !containsFailure(c.failures, failure) {
+			c.failures = append(c.failures, failure)
+		}
+	}
+	return nil
+}
+
+func containsFailure(failures []PRError, target PRError) bool {
+	for _, failure := range failures {
+		if target.URL != "" && failure.URL == target.URL {
+			return true
+		}
+		if target.URL == "" && failure.URL == "" && failure.Error() == target.Error() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/pkg/api"
+)
+
+func TestPRClient_buildRESTPRs_PartialResults(t *testing.T) {
+	items := []searchItem{
+		{Title: "OK", URL: "https://api.github.com/repos/owner/ok/issues/1", HTMLURL: "https://github.com/owner/ok/pull/1", State: "open", Number: 1, roles: []Role{RoleAuthor}},
+		{Title: "SSO", URL: "https://api.github.com/repos/owner/sso/issues/2", HTMLURL: "https://github.com/owner/sso/pull/2", State: "open", Number: 2, roles: []Role{RoleAuthor}},
+	}
+
+	tests := []struct {
+		name    string
+		strict  bool
+		wantPRs int
+		wantErr bool
+	}{
+		{name: "失敗したPRを除いて返す", wantPRs: 1},
+		{name: "strictでは全体を失敗させる", strict: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+				switch {
+				case strings.HasPrefix(r.URL.Path, "/repos/owner/sso/"):
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"message":"Resource protected by organization SAML enforcement."}`))
+				case r.URL.Path == "/user":
+					w.Write([]byte(`{"login":"testuser"}`))
+				case pullDetailPathRE.MatchString(r.URL.Path):
+					w.Write([]byte(`{}`))
+				default:
+					w.Write([]byte(`[]`))
+				}
+			})
+			defer server.Close()
+			client.SetStrict(tt.strict)
+
+			prs, err := client.buildRESTPRs(items, utcRange(t, "2024-02-04", "2024-02-04"), "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildRESTPRs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var httpErr api.HTTPError
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusForbidden {
+					t.Errorf("buildRESTPRs() error = %v, want 403", err)
+				}
+				return
+			}
+
+			if len(prs) != tt.wantPRs || prs[0].Title != "OK" {
+				t.Errorf("buildRESTPRs() = %+v, want only OK", prs)
+			}
+			failures := client.Errors()
+			if len(failures) != 1 {
+				t.Fatalf("Errors() returned %d failures, want 1", len(failures))
+			}
+			if f := failures[0]; f.Repository != "owner/sso" || f.Number != 2 || f.URL != "https://github.com/owner/sso/pull/2" {
+				t.Errorf("Errors()[0] = %+v", f)
+			}
+		})
+	}
+}
+
+func TestPRClient_buildRESTPRs_DetailFailure(t *testing.T) {
+	items := []searchItem{
+		{Title: "OK", URL: "https://api.github.com/repos/owner/repo/issues/1", HTMLURL: "https://github.com/owner/repo/pull/1", State: "closed", Number: 1, roles: []Role{RoleAuthor}},
+		{Title: "NG", URL: "https://api.github.com/repos/owner/repo/issues/2", HTMLURL: "https://github.com/owner/repo/pull/2", State: "closed", Number: 2, roles: []Role{RoleAuthor}},
+	}
+
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		switch {
+		case r.URL.Path == "/repos/owner/repo/pulls/2":
+			// コミット一覧などは取得できてもPRの詳細だけ取得できない場合
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"message":"Server Error"}`))
+		case r.URL.Path == "/user":
+			w.Write([]byte(`{"login":"testuser"}`))
+		case pullDetailPathRE.MatchString(r.URL.Path):
+			w.Write([]byte(`{"merged":true,"base":{"ref":"main"}}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+	defer server.Close()
+
+	prs, err := client.buildRESTPRs(items, utcRange(t, "2024-02-04", "2024-02-04"), "")
+	if err != nil {
+		t.Fatalf("buildRESTPRs() error = %v", err)
+	}
+	if len(prs) != 1 || prs[0].Title != "OK" || !prs[0].Merged || prs[0].Base != "main" {
+		t.Errorf("buildRESTPRs() = %+v, want only merged OK", prs)
+	}
+	failures := client.Errors()
+	if len(failures) != 1 {
+		t.Fatalf("Errors() returned %d failures, want 1", len(failures))
+	}
+	var httpErr api.HTTPError
+	if f := failures[0]; f.Number != 2 || !errors.As(f, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Errors()[0] = %+v, want 502 for #2", f)
+	}
+}
+
+func TestPRClient_collectFailures_Dedupe(t *testing.T) {
+	sso := newPRError("owner/sso", 2, "https://github.com/owner/sso/pull/2", errors.New("403"))
+	other := newPRError("owner/sso", 3, "https://github.com/owner/sso/pull/3", errors.New("403"))
+	search := PRError{Message: "Resource protected by organization SAML enforcement."}
+
+	tests := []struct {
+		name  string
+		calls [][]PRError
+		want  int
+	}{
+		{name: "同じPRの失敗は1件にまとめる", calls: [][]PRError{{sso}, {sso}}, want: 1},
+		{name: "別のPRの失敗はそれぞれ記録する", calls: [][]PRError{{sso}, {other}}, want: 2},
+		{name: "PRのない失敗は同じメッセージをまとめる", calls: [][]PRError{{search}, {search, sso}}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &PRClient{}
+			for _, failures := range tt.calls {
+				if err := client.collectFailures(failures); err != nil {
+					t.Fatalf("collectFailures() error = %v", err)
+				}
+			}
+			if got := client.Errors(); len(got) != tt.want {
+				t.Errorf("Errors() = %+v, want %d failures", got, tt.want)
+			}
+		})
+	}
+}
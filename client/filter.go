@@ -0,0 +1,107 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Filters はPRの絞り込みの条件。Search APIで指定できる条件は検索のクエリにし、
+// 指定できない条件のうちワイルドカードのリポジトリとボットの除外は詳細の取得前にfilterReposで、
+// 複数のベースブランチは詳細の取得後にfilterPRsで取り除く
+type Filters struct {
+	// 除外するリポジトリ（owner/repo形式、ワイルドカードも使える）
+	ExcludeRepos []string
+	// すべてのラベルが付いたPRだけを検索する
+	Labels []string
+	// いずれかのラベルが付いたPRを除外する
+	ExcludeLabels []string
+	// いずれかに一致するベースブランチのPRだけを検索する（release/*のようなワイルドカードも使える）
+	Bases []string
+	// ボットが作成したPRを除外する
+	ExcludeBots bool
+}
+
+// qualifiers は条件のうちSearch APIで指定できるものを検索の条件にする
+func (f Filters) qualifiers() []string {
+	var qualifiers []string
+	for _, repo := range f.ExcludeRepos {
+		if !hasWildcard(repo) {
+			qualifiers = append(qualifiers, "-repo:"+repo)
+		}
+	}
+	for _, label := range f.Labels {
+		qualifiers = append(qualifiers, "label:"+quoteQualifier(label))
+	}
+	for _, label := range f.ExcludeLabels {
+		qualifiers = append(qualifiers, "-label:"+quoteQualifier(label))
+	}
+	if !f.filtersBases() && len(f.Bases) == 1 {
+		qualifiers = append(qualifiers, "base:"+quoteQualifier(f.Bases[0]))
+	}
+	return qualifiers
+}
+
+// filtersBases はベースブランチをfilterPRsで絞り込むかどうかを返す。
+// base:を複数指定するとすべてに一致するPRになるため、ワイルドカードを含まない1つの場合だけ検索の条件にする
+func (f Filters) filtersBases() bool {
+	return len(f.Bases) > 1 || (len(f.Bases) == 1 && hasWildcard(f.Bases[0]))
+}
+
+// quoteQualifier は空白を含む値を引用符で囲む
+func quoteQualifier(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	return value
+}
+
+// wildcardExcludeRepos は検索のクエリにできないワイルドカードの除外リポジトリを返す
+func (f Filters) wildcardExcludeRepos() []string {
+	var repos []string
+	for _, repo := range f.ExcludeRepos {
+		if hasWildcard(repo) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+// filterPRs はベースブランチが指定に一致しないPRを取り除く。
+// REST APIではベースブランチがPRの詳細にしか含まれないため、詳細を取得してから絞り込む
+func filterPRs(prs []PullRequest, f Filters) []PullRequest {
+	if !f.filtersBases() {
+		return prs
+	}
+
+	var filtered []PullRequest
+	for _, pr := range prs {
+		if matchBase(f.Bases, pr.Base) {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered
+}
+
+// matchBase はベースブランチがいずれかの指定に一致するかを返す
+func matchBase(bases []string, base string) bool {
+	for _, pattern := range bases {
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isBot はdependabot[bot]のようなGitHub Appのユーザーかどうかを返す
+func isBot(login string) bool {
+	return strings.HasSuffix(login, "[bot]")
+}
+
+// ValidateBase はベースブランチの指定を検証する
+func ValidateBase(base string) error {
+	if _, err := path.Match(base, ""); base == "" || err != nil {
+		return fmt.Errorf("不正なベースブランチ: %s", base)
+	}
+	return nil
+}
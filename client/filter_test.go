@@ -0,0 +1,139 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFilters_Qualifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  Filters
+		expected []string
+	}{
+		{
+			name:     "指定なし",
+			filters:  Filters{},
+			expected: nil,
+		},
+		{
+			name: "リポジトリとラベル",
+			filters: Filters{
+				ExcludeRepos:  []string{"owner/sandbox", "owner/tmp-*"},
+				Labels:        []string{"bug", "good first issue"},
+				ExcludeLabels: []string{"dependencies"},
+			},
+			expected: []string{"-repo:owner/sandbox", "label:bug", `label:"good first issue"`, "-label:dependencies"},
+		},
+		{
+			name:     "ベースブランチを1つ指定",
+			filters:  Filters{Bases: []string{"main"}},
+			expected: []string{"base:main"},
+		},
+		{
+			name:     "ベースブランチを複数指定",
+			filters:  Filters{Bases: []string{"main", "develop"}},
+			expected: nil,
+		},
+		{
+			name:     "ベースブランチのワイルドカード",
+			filters:  Filters{Bases: []string{"release/*"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filters.qualifiers(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("qualifiers() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterPRs(t *testing.T) {
+	prs := []PullRequest{
+		{Number: 1, Base: "main"},
+		{Number: 2, Base: "release/1.0"},
+		{Number: 3, Base: "develop"},
+		{Number: 4, Base: "feature/x"},
+	}
+	tests := []struct {
+		name     string
+		filters  Filters
+		expected []int
+	}{
+		{
+			name:     "指定なし",
+			filters:  Filters{},
+			expected: []int{1, 2, 3, 4},
+		},
+		{
+			name:     "1つのベースブランチは検索で絞り込む",
+			filters:  Filters{Bases: []string{"main"}},
+			expected: []int{1, 2, 3, 4},
+		},
+		{
+			name:     "複数のベースブランチ",
+			filters:  Filters{Bases: []string{"main", "release/*"}},
+			expected: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, pr := range filterPRs(prs, tt.filters) {
+				got = append(got, pr.Number)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("filterPRs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPRClient_FetchTodaysPRs_FiltersBeforeDetails(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		switch r.URL.Path {
+		case "/search/issues":
+			items := []searchItem{
+				{Number: 1, URL: "https://api.github.com/repos/owner/api/issues/1", State: "open"},
+				{Number: 2, URL: "https://api.github.com/repos/owner/sandbox-x/issues/2", State: "open"},
+				{Number: 3, URL: "https://api.github.com/repos/owner/api/issues/3", State: "open"},
+			}
+			items[0].User.Login = "me"
+			items[1].User.Login = "me"
+			items[2].User.Login = "dependabot[bot]"
+			json.NewEncoder(w).Encode(searchResponse{Items: items, Total: len(items)})
+		case "/repos/owner/api/pulls/1":
+			fmt.Fprint(w, `{}`)
+		case "/repos/owner/api/pulls/1/commits", "/repos/owner/api/issues/1/events":
+			fmt.Fprint(w, `[]`)
+		default:
+			// 除外したPRの詳細は取得しない
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Resource protected by organization SAML enforcement."}`)
+		}
+	})
+	defer server.Close()
+
+	prs, err := client.FetchTodaysPRs(SearchOptions{
+		Range:   utcRange(t, "2024-01-01", "2024-01-31"),
+		User:    "me",
+		Filters: Filters{ExcludeRepos: []string{"owner/sandbox-*"}, ExcludeBots: true},
+	})
+	if err != nil {
+		t.Fatalf("FetchTodaysPRs() error = %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 1 {
+		t.Errorf("FetchTodaysPRs() = %v, want only #1", prs)
+	}
+	if failures := client.Errors(); len(failures) != 0 {
+		t.Errorf("Errors() = %v, want none", failures)
+	}
+}
@@ -0,0 +1,688 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// 1ページあたりの取得件数。コミット一覧も含めるためRESTより少なくする
+const graphQLPageSize = 50
+
+const searchPRsQuery = `query SearchPullRequests($query: String!, $first: Int!, $after: String, $withReviews: Boolean!, $withChecks: Boolean!, $withReviewStatus: Boolean!) {
+	viewer {
+		login
+	}
+	search(query: $query, type: ISSUE, first: $first, after: $after) {
+		issueCount
+		pageInfo {
+			hasNextPage
+			endCursor
+		}
+		nodes {
+			... on PullRequest {
+				title
+				url
+				number
+				state
+				merged
+				isDraft
+				mergedAt
+				mergedBy {
+					login
+				}
+				author {
+					login
+					__typename
+				}
+				createdAt
+				updatedAt
+				closedAt
+				reviewDecision
+				baseRefName
+				additions
+				deletions
+				changedFiles
+				body
+				labels(first: 20) {
+					nodes {
+						name
+						color
+					}
+				}
+				milestone {
+					title
+				}
+				assignees(first: 10) {
+					nodes {
+						login
+					}
+				}
+				closingIssuesReferences(first: 20) {
+					nodes {
+						number
+						repository {
+							nameWithOwner
+						}
+					}
+				}
+				repository {
+					nameWithOwner
+					url
+				}
+				commits(last: 100) {
+					totalCount
+					nodes {
+						commit {
+							authoredDate
+							author {
+								user {
+									login
+								}
+							}
+							committer {
+								user {
+									login
+								}
+							}
+						}
+					}
+				}
+				timelineItems(itemTypes: [READY_FOR_REVIEW_EVENT], last: 20) {
+					totalCount
+					nodes {
+						... on ReadyForReviewEvent {
+							createdAt
+							actor {
+								login
+							}
+						}
+					}
+				}
+				reviews(last: 50) @include(if: $withReviews) {
+					totalCount
+					nodes {
+						state
+						submittedAt
+						author {
+							login
+						}
+						comments(first: 30) {
+							totalCount
+							nodes {
+								createdAt
+								author {
+									login
+								}
+							}
+						}
+					}
+				}
+				comments(last: 100) @include(if: $withReviews) {
+					totalCount
+					nodes {
+						createdAt
+						author {
+							login
+						}
+					}
+				}
+				headRefOid
+				headCommit: commits(last: 1) @include(if: $withChecks) {
+					nodes {
+						commit {
+							statusCheckRollup {
+								contexts(first: 100) {
+									pageInfo {
+										hasNextPage
+									}
+									nodes {
+										__typename
+										... on CheckRun {
+											name
+											status
+											conclusion
+										}
+										... on StatusContext {
+											context
+											state
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+				reviewRequests(first: 100) @include(if: $withReviewStatus) {
+					totalCount
+					nodes {
+						requestedReviewer {
+							__typename
+							... on User {
+								login
+							}
+							... on Team {
+								slug
+							}
+						}
+					}
+				}
+				latestReviews(first: 100) @include(if: $withReviewStatus) {
+					totalCount
+					nodes {
+						...statusReview
+					}
+				}
+				latestOpinionatedReviews(first: 100) @include(if: $withReviewStatus) {
+					totalCount
+					nodes {
+						...statusReview
+					}
+				}
+			}
+		}
+	}
+}
+
+fragment statusReview on PullRequestReview {
+	state
+	submittedAt
+	author {
+		login
+	}
+}`
+
+type graphQLCommitUser struct {
+	User *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (u graphQLCommitUser) login() string {
+	if u.User == nil {
+		return ""
+	}
+	return u.User.Login
+}
+
+type graphQLActor struct {
+	Login string `json:"login"`
+	// PRの作成者でのみ取得する
+	Typename string `json:"__typename"`
+}
+
+type graphQLPullRequest struct {
+	Title          string        `json:"title"`
+	URL            string        `json:"url"`
+	Number         int           `json:"number"`
+	State          string        `json:"state"`
+	Merged         bool          `json:"merged"`
+	IsDraft        bool          `json:"isDraft"`
+	MergedAt       *time.Time    `json:"mergedAt"`
+	MergedBy       *graphQLActor `json:"mergedBy"`
+	Author         *graphQLActor `json:"author"`
+	CreatedAt      time.Time     `json:"createdAt"`
+	UpdatedAt      time.Time     `json:"updatedAt"`
+	ClosedAt       *time.Time    `json:"closedAt"`
+	ReviewDecision string        `json:"reviewDecision"`
+	BaseRefName    string        `json:"baseRefName"`
+	Additions      int           `json:"additions"`
+	Deletions      int           `json:"deletions"`
+	ChangedFiles   int           `json:"changedFiles"`
+	Body           string        `json:"body"`
+	Labels         struct {
+		Nodes []Label `json:"nodes"`
+	} `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Assignees struct {
+		Nodes []graphQLActor `json:"nodes"`
+	} `json:"assignees"`
+	ClosingIssuesReferences struct {
+		Nodes []struct {
+			Number     int `json:"number"`
+			Repository struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"repository"`
+		} `json:"nodes"`
+	} `json:"closingIssuesReferences"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+		URL           string `json:"url"`
+	} `json:"repository"`
+	Commits struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			Commit struct {
+				AuthoredDate time.Time         `json:"authoredDate"`
+				Author       graphQLCommitUser `json:"author"`
+				Committer    graphQLCommitUser `json:"committer"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+	TimelineItems struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			CreatedAt time.Time     `json:"createdAt"`
+			Actor     *graphQLActor `json:"actor"`
+		} `json:"nodes"`
+	} `json:"timelineItems"`
+	Reviews struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			State       string        `json:"state"`
+			SubmittedAt *time.Time    `json:"submittedAt"`
+			Author      *graphQLActor `json:"author"`
+			Comments    struct {
+				TotalCount int              `json:"totalCount"`
+				Nodes      []graphQLComment `json:"nodes"`
+			} `json:"comments"`
+		} `json:"nodes"`
+	} `json:"reviews"`
+	Comments struct {
+		TotalCount int              `json:"totalCount"`
+		Nodes      []graphQLComment `json:"nodes"`
+	} `json:"comments"`
+
+	// 以下はCIの状態とレビュー状況を取得する場合のみ
+	HeadRefOid string `json:"headRefOid"`
+	HeadCommit *struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					Contexts struct {
+						PageInfo struct {
+							HasNextPage bool `json:"hasNextPage"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							Typename   string `json:"__typename"`
+							Name       string `json:"name"`
+							Status     string `json:"status"`
+							Conclusion string `json:"conclusion"`
+							Context    string `json:"context"`
+							State      string `json:"state"`
+						} `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"headCommit"`
+	ReviewRequests *struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			RequestedReviewer *struct {
+				Typename string `json:"__typename"`
+				Login    string `json:"login"`
+				Slug     string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	LatestReviews            *graphQLReviewConnection `json:"latestReviews"`
+	LatestOpinionatedReviews *graphQLReviewConnection `json:"latestOpinionatedReviews"`
+}
+
+type graphQLReviewConnection struct {
+	TotalCount int `json:"totalCount"`
+	Nodes      []struct {
+		State       string        `json:"state"`
+		SubmittedAt *time.Time    `json:"submittedAt"`
+		Author      *graphQLActor `json:"author"`
+	} `json:"nodes"`
+}
+
+// truncated は件数の上限を超えて一部しか取得できていないかどうかを返す
+func (r *graphQLReviewConnection) truncated() bool {
+	return r != nil && r.TotalCount > len(r.Nodes)
+}
+
+// status はオープンなPRのCIの状態とレビュー状況を、fetchStatusで使い回せる形にする。
+// レビュー状況が一部しか取得できていない場合はnilを返し、REST APIで取得し直す。
+// チェックが一部しか取得できていない場合はCIの状態だけREST APIで取得し直す。
+func (n graphQLPullRequest) status() *pullStatus {
+	if n.State != "OPEN" {
+		return nil
+	}
+	if (n.ReviewRequests != nil && n.ReviewRequests.TotalCount > len(n.ReviewRequests.Nodes)) ||
+		n.LatestReviews.truncated() || n.LatestOpinionatedReviews.truncated() {
+		return nil
+	}
+
+	status := &pullStatus{}
+	status.Head.SHA = n.HeadRefOid
+	if n.HeadCommit != nil {
+		status.hasChecks = true
+		for _, commit := range n.HeadCommit.Nodes {
+			rollup := commit.Commit.StatusCheckRollup
+			if rollup == nil {
+				continue
+			}
+			if rollup.Contexts.PageInfo.HasNextPage {
+				status.hasChecks = false
+				break
+			}
+			var runs []checkRun
+			var statuses []commitStatus
+			for _, context := range rollup.Contexts.Nodes {
+				switch context.Typename {
+				case "CheckRun":
+					runs = append(runs, checkRun{Name: context.Name, Status: strings.ToLower(context.Status), Conclusion: strings.ToLower(context.Conclusion)})
+				case "StatusContext":
+					statuses = append(statuses, commitStatus{Context: context.Context, State: strings.ToLower(context.State)})
+				}
+			}
+			status.checkState, status.failingChecks = summarizeChecks(runs, statuses)
+		}
+	}
+	if n.ReviewRequests != nil {
+		for _, request := range n.ReviewRequests.Nodes {
+			reviewer := request.RequestedReviewer
+			switch {
+			case reviewer == nil:
+			case reviewer.Typename == "Team":
+				status.RequestedTeams = append(status.RequestedTeams, requestedTeam{Slug: reviewer.Slug})
+			case reviewer.Login != "":
+				status.RequestedReviewers = append(status.RequestedReviewers, searchUser{Login: reviewer.Login})
+			}
+		}
+	}
+	if n.LatestReviews != nil {
+		// レビューした人はlatestReviewsから、承認・変更依頼の状態はlatestOpinionatedReviewsから求める
+		// （コメントだけのレビューが後にあってもREST APIと同じく承認は有効なままにする）
+		status.hasReviews = true
+		for _, conn := range []*graphQLReviewConnection{n.LatestReviews, n.LatestOpinionatedReviews} {
+			if conn == nil {
+				continue
+			}
+			for _, review := range conn.Nodes {
+				if review.SubmittedAt == nil {
+					continue
+				}
+				status.reviews = append(status.reviews, prReview{
+					AuthorLogin: review.Author.login(),
+					State:       review.State,
+					SubmittedAt: *review.SubmittedAt,
+				})
+			}
+		}
+	}
+	return status
+}
+
+// truncated はコミットやレビューなどの一覧が1回のクエリで取得できる件数を超えていて、
+// 関わり方の判定に使う情報の一部が欠けているかどうかを返す
+func (n graphQLPullRequest) truncated() bool {
+	if n.Commits.TotalCount > len(n.Commits.Nodes) ||
+		n.TimelineItems.TotalCount > len(n.TimelineItems.Nodes) ||
+		n.Reviews.TotalCount > len(n.Reviews.Nodes) ||
+		n.Comments.TotalCount > len(n.Comments.Nodes) {
+		return true
+	}
+	for _, review := range n.Reviews.Nodes {
+		if review.Comments.TotalCount > len(review.Comments.Nodes) {
+			return true
+		}
+	}
+	return false
+}
+
+type graphQLComment struct {
+	CreatedAt time.Time     `json:"createdAt"`
+	Author    *graphQLActor `json:"author"`
+}
+
+// login は削除済みユーザーなどでnullの場合に空文字列を返す
+func (a *graphQLActor) login() string {
+	if a == nil {
+		return ""
+	}
+	return a.Login
+}
+
+type graphQLSearchResponse struct {
+	Viewer struct {
+		Login string `json:"login"`
+	} `json:"viewer"`
+	Search struct {
+		IssueCount int `json:"issueCount"`
+		PageInfo   struct {
+			HasNextPage bool   `json:"hasNextPage"`
+			EndCursor   string `json:"endCursor"`
+		} `json:"pageInfo"`
+		Nodes []graphQLPullRequest `json:"nodes"`
+	} `json:"search"`
+}
+
+// hasSearchResult は検索結果の一部でも返されたかどうかを返す
+func (r graphQLSearchResponse) hasSearchResult() bool {
+	return len(r.Search.Nodes) > 0 || r.Search.PageInfo.HasNextPage || r.Search.PageInfo.EndCursor != ""
+}
+
+// fetchGraphQLSearchPage はGraphQL APIで検索結果を1ページ取得する。
+// マージ状態やコミットの作成者も同じクエリで取得する。
+// withReviewsが指定された場合はレビューとコメントも、withChecksとwithReviewStatusが指定された場合は
+// オープンなPRのCIの状態とレビュー状況の判定に使う情報も取得する。
+func (c *PRClient) fetchGraphQLSearchPage(query, cursor string, withReviews, withChecks, withReviewStatus bool) (searchResponse, string, error) {
+	variables := map[string]interface{}{
+		"query":            query,
+		"first":            graphQLPageSize,
+		"withReviews":      withReviews,
+		"withChecks":       withChecks,
+		"withReviewStatus": withReviewStatus,
+	}
+	if cursor != "" {
+		variables["after"] = cursor
+	}
+
+	// デバッグ出力
+	c.debugPrint("GraphQL検索: %s (cursor: %s)\n", query, cursor)
+
+	var response graphQLSearchResponse
+	if err := c.gql.Do(searchPRsQuery, variables, &response); err != nil {
+		// SSOで保護されたリポジトリなどはエラーとともに取得できた分だけが返される
+		var gqlErr api.GQLError
+		if !errors.As(err, &gqlErr) {
+			return searchResponse{}, "", err
+		}
+		// レート制限や検索構文のエラーでは検索結果自体が返されないため、REST APIと同じく全体の失敗とする
+		if !response.hasSearchResult() {
+			return searchResponse{}, "", err
+		}
+		failures := make([]PRError, 0, len(gqlErr.Errors))
+		for _, item := range gqlErr.Errors {
+			failures = append(failures, PRError{Message: item.Message, err: gqlErr})
+		}
+		if err := c.collectFailures(failures); err != nil {
+			return searchResponse{}, "", err
+		}
+	}
+
+	// 作成者の判定に使うユーザー名をキャッシュしておく
+	if login := response.Viewer.Login; login != "" {
+		c.userCacheMux.Lock()
+		if c.userCache == nil {
+			c.userCache = &login
+		}
+		c.userCacheMux.Unlock()
+	}
+
+	result := searchResponse{Total: response.Search.IssueCount}
+	for _, node := range response.Search.Nodes {
+		// PR以外（Issueなど）は空のノードとして返される
+		if node.Number == 0 {
+			continue
+		}
+
+		item := searchItem{
+			Title:     node.Title,
+			URL:       fmt.Sprintf("%srepos/%s/issues/%d", apiPrefix(c.host), node.Repository.NameWithOwner, node.Number),
+			HTMLURL:   node.URL,
+			CreatedAt: node.CreatedAt,
+			UpdatedAt: node.UpdatedAt,
+			ClosedAt:  node.ClosedAt,
+			State:     restState(node.State),
+			Draft:     node.IsDraft,
+			Number:    node.Number,
+			Body:      node.Body,
+			Labels:    node.Labels.Nodes,
+			Milestone: node.Milestone,
+
+			merged:         node.Merged,
+			reviewDecision: node.ReviewDecision,
+			baseRef:        node.BaseRefName,
+			additions:      node.Additions,
+			deletions:      node.Deletions,
+			changedFiles:   node.ChangedFiles,
+			commits:        node.Commits.TotalCount,
+			truncated:      node.truncated(),
+			status:         node.status(),
+			details: prDetails{
+				mergedAt: node.MergedAt,
+				mergedBy: node.MergedBy.login(),
+				commits:  make([]prCommit, 0, len(node.Commits.Nodes)),
+			},
+		}
+		item.User.Login = node.Author.login()
+		if node.Author != nil && node.Author.Typename == "Bot" {
+			// REST APIと同じくボットのユーザー名には[bot]を付ける
+			item.User.Login += "[bot]"
+		}
+		for _, assignee := range node.Assignees.Nodes {
+			item.Assignees = append(item.Assignees, searchUser{Login: assignee.Login})
+		}
+		for _, issue := range node.ClosingIssuesReferences.Nodes {
+			item.closingIssues = append(item.closingIssues, IssueRef{Repository: issue.Repository.NameWithOwner, Number: issue.Number})
+		}
+		item.Repository.FullName = node.Repository.NameWithOwner
+		item.Repository.HTMLURL = node.Repository.URL
+		for _, commit := range node.Commits.Nodes {
+			item.details.commits = append(item.details.commits, prCommit{
+				AuthorLogin:    commit.Commit.Author.login(),
+				CommitterLogin: commit.Commit.Committer.login(),
+				AuthoredAt:     commit.Commit.AuthoredDate,
+			})
+		}
+		for _, event := range node.TimelineItems.Nodes {
+			item.details.events = append(item.details.events, prEvent{
+				Event:      "ready_for_review",
+				ActorLogin: event.Actor.login(),
+				CreatedAt:  event.CreatedAt,
+			})
+		}
+		for _, review := range node.Reviews.Nodes {
+			// 保留中のレビューは提出日時がない
+			if review.SubmittedAt == nil {
+				continue
+			}
+			item.details.reviews = append(item.details.reviews, prReview{
+				AuthorLogin: review.Author.login(),
+				State:       review.State,
+				SubmittedAt: *review.SubmittedAt,
+			})
+			for _, comment := range review.Comments.Nodes {
+				item.details.comments = append(item.details.comments, prComment{
+					AuthorLogin: comment.Author.login(),
+					CreatedAt:   comment.CreatedAt,
+				})
+			}
+		}
+		for _, comment := range node.Comments.Nodes {
+			item.details.comments = append(item.details.comments, prComment{
+				AuthorLogin: comment.Author.login(),
+				CreatedAt:   comment.CreatedAt,
+			})
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	next := ""
+	if response.Search.PageInfo.HasNextPage {
+		next = response.Search.PageInfo.EndCursor
+	}
+	return result, next, nil
+}
+
+// restState はGraphQLのPRの状態をREST APIの表記に合わせる
+func restState(state string) string {
+	switch state {
+	case "OPEN":
+		return "open"
+	default:
+		// MERGEDもREST APIではclosedとして扱われる
+		return "closed"
+	}
+}
+
+// buildGraphQLPRs はGraphQLで取得した検索結果からPRを組み立てる。
+// REST APIと同じ判定を行い、コミットやレビューの一覧が途中までしか取得できていないPRだけ
+// REST APIで詳細を取得し直す。
+func (c *PRClient) buildGraphQLPRs(items []searchItem, rng DateRange, username string) ([]PullRequest, error) {
+	username, err := c.resolveUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []PullRequest
+	var failures []PRError
+	for _, item := range items {
+		pr := PullRequest{
+			Title:          item.Title,
+			URL:            convertToPullsURL(item.URL),
+			HTMLURL:        item.HTMLURL,
+			CreatedAt:      item.CreatedAt,
+			UpdatedAt:      item.UpdatedAt,
+			ClosedAt:       item.ClosedAt,
+			State:          item.State,
+			Merged:         item.merged,
+			Draft:          item.Draft,
+			Number:         item.Number,
+			Author:         item.User.Login,
+			Roles:          item.roles,
+			ReviewDecision: item.reviewDecision,
+			Base:           item.baseRef,
+			Additions:      item.additions,
+			Deletions:      item.deletions,
+			ChangedFiles:   item.changedFiles,
+			Commits:        item.commits,
+			Labels:         item.Labels,
+			Milestone:      item.milestone(),
+			Assignees:      item.assignees(),
+			ClosingIssues:  item.closingIssueRefs(item.Repository.FullName),
+			Host:           c.host,
+			User:           username,
+			status:         item.status,
+		}
+		pr.Repository.FullName = item.Repository.FullName
+
+		withReviews := false
+		for _, role := range item.roles {
+			withReviews = withReviews || role.needsReviews()
+		}
+
+		if item.truncated {
+			// 一覧が欠けたまま判定するとREST APIと結果が変わるため取得し直す
+			c.debugPrint("一覧が途中までのためREST APIで取得: %s#%d\n", pr.Repository.FullName, pr.Number)
+			if err := c.detectContribution(&pr, rng, withReviews, username); err != nil {
+				c.debugPrint("活動情報の取得に失敗: %v\n", err)
+				failures = append(failures, newPRError(pr.Repository.FullName, pr.Number, pr.HTMLURL, fmt.Errorf("活動情報の取得に失敗: %w", err)))
+				continue
+			}
+			prs = append(prs, pr)
+			continue
+		}
+
+		if withReviews {
+			pr.ReviewState, pr.CommentCount = summarizeReviews(item.details, username, rng)
+		}
+		pr.Activities = collectActivities(pr, username, rng, item.details)
+		pr.Contribution = primaryContribution(pr.Activities)
+		prs = append(prs, pr)
+	}
+	if err := c.collectFailures(failures); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
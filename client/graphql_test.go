@@ -0,0 +1,433 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// モックのGraphQLクライアント
+type mockGQLClient struct {
+	t       *testing.T
+	handler func(query string, variables map[string]interface{}) interface{}
+	// errors が指定された場合は実際のクライアントと同じくdataを詰めたうえでGQLErrorを返す
+	errors []api.GQLErrorItem
+}
+
+func (c *mockGQLClient) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return c.DoWithContext(context.Background(), query, variables, response)
+}
+
+func (c *mockGQLClient) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	// 実際のクライアントと同じくJSONを経由して値を詰める
+	data, err := json.Marshal(c.handler(query, variables))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return err
+	}
+	if len(c.errors) > 0 {
+		return api.GQLError{Errors: c.errors}
+	}
+	return nil
+}
+
+func (c *mockGQLClient) Mutate(name string, mutation interface{}, variables map[string]interface{}) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (c *mockGQLClient) MutateWithContext(ctx context.Context, name string, mutation interface{}, variables map[string]interface{}) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (c *mockGQLClient) Query(name string, query interface{}, variables map[string]interface{}) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (c *mockGQLClient) QueryWithContext(ctx context.Context, name string, query interface{}, variables map[string]interface{}) error {
+	return fmt.Errorf("not implemented")
+}
+
+func graphQLNode(number int, state string, merged bool) map[string]interface{} {
+	return map[string]interface{}{
+		"title":          fmt.Sprintf("PR %d", number),
+		"url":            fmt.Sprintf("https://github.com/owner/repo/pull/%d", number),
+		"number":         number,
+		"state":          state,
+		"merged":         merged,
+		"isDraft":        false,
+		"createdAt":      "2024-02-04T01:00:00Z",
+		"updatedAt":      "2024-02-04T02:00:00Z",
+		"reviewDecision": "APPROVED",
+		"baseRefName":    "main",
+		"additions":      10,
+		"deletions":      2,
+		"changedFiles":   1,
+		"body":           "Fixes #10",
+		"labels": map[string]interface{}{
+			"nodes": []interface{}{map[string]interface{}{"name": "bug", "color": "d73a4a"}},
+		},
+		"milestone": map[string]interface{}{"title": "v1.0"},
+		"assignees": map[string]interface{}{
+			"nodes": []interface{}{map[string]interface{}{"login": "alice"}},
+		},
+		"closingIssuesReferences": map[string]interface{}{
+			"nodes": []interface{}{
+				map[string]interface{}{"number": 10, "repository": map[string]interface{}{"nameWithOwner": "owner/repo"}},
+				map[string]interface{}{"number": 3, "repository": map[string]interface{}{"nameWithOwner": "other/lib"}},
+			},
+		},
+		"repository": map[string]interface{}{
+			"nameWithOwner": "owner/repo",
+			"url":           "https://github.com/owner/repo",
+		},
+		"commits": map[string]interface{}{
+			"totalCount": 1,
+			"nodes": []interface{}{
+				map[string]interface{}{
+					"commit": map[string]interface{}{
+						"authoredDate": "2024-02-04T01:00:00Z",
+						"author":       map[string]interface{}{"user": map[string]interface{}{"login": "testuser"}},
+						"committer":    map[string]interface{}{"user": nil},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPRClient_FetchTodaysPRs_GraphQL(t *testing.T) {
+	now := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var cursors []interface{}
+	client := &PRClient{
+		gql: &mockGQLClient{t: t, handler: func(query string, variables map[string]interface{}) interface{} {
+			if variables["query"] != "is:pr updated:2024-02-04T00:00:00Z..2024-02-04T23:59:59Z author:@me" {
+				t.Errorf("query = %v", variables["query"])
+			}
+			cursors = append(cursors, variables["after"])
+
+			page := map[string]interface{}{
+				"viewer": map[string]interface{}{"login": "testuser"},
+			}
+			if variables["after"] == nil {
+				page["search"] = map[string]interface{}{
+					"issueCount": 2,
+					"pageInfo":   map[string]interface{}{"hasNextPage": true, "endCursor": "cursor1"},
+					"nodes":      []interface{}{graphQLNode(1, "MERGED", true)},
+				}
+			} else {
+				node := graphQLNode(2, "OPEN", false)
+				node["author"] = map[string]interface{}{"login": "dependabot", "__typename": "Bot"}
+				page["search"] = map[string]interface{}{
+					"issueCount": 2,
+					"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": "cursor2"},
+					"nodes":      []interface{}{node},
+				}
+			}
+			return page
+		}},
+		backend:     BackendGraphQL,
+		commitCache: make(map[string][]prCommit),
+	}
+
+	prs, err := client.FetchTodaysPRs(SearchOptions{Range: Today(time.UTC)})
+	if err != nil {
+		t.Fatalf("FetchTodaysPRs() error = %v", err)
+	}
+	if len(cursors) != 2 || cursors[1] != "cursor1" {
+		t.Errorf("cursors = %v, want [<nil> cursor1]", cursors)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("FetchTodaysPRs() returned %d PRs, want 2", len(prs))
+	}
+
+	merged := prs[0]
+	if !merged.Merged || merged.State != "closed" {
+		t.Errorf("PR #1 Merged = %v, State = %v, want true, closed", merged.Merged, merged.State)
+	}
+	if merged.URL != "https://api.github.com/repos/owner/repo/pulls/1" {
+		t.Errorf("PR #1 URL = %v", merged.URL)
+	}
+	if merged.Repository.FullName != "owner/repo" {
+		t.Errorf("PR #1 Repository.FullName = %v, want owner/repo", merged.Repository.FullName)
+	}
+	if merged.Contribution != ContributionCommitted {
+		t.Errorf("PR #1 Contribution = %v, want committed", merged.Contribution)
+	}
+	if merged.Base != "main" {
+		t.Errorf("PR #1 Base = %v, want main", merged.Base)
+	}
+	if merged.ReviewDecision != "APPROVED" {
+		t.Errorf("PR #1 ReviewDecision = %v, want APPROVED", merged.ReviewDecision)
+	}
+	if merged.Additions != 10 || merged.Deletions != 2 || merged.ChangedFiles != 1 || merged.Commits != 1 {
+		t.Errorf("PR #1 diff stats = +%d -%d, %d files, %d commits, want +10 -2, 1 file, 1 commit",
+			merged.Additions, merged.Deletions, merged.ChangedFiles, merged.Commits)
+	}
+	if len(merged.Labels) != 1 || merged.Labels[0] != (Label{Name: "bug", Color: "d73a4a"}) ||
+		merged.Milestone != "v1.0" || len(merged.Assignees) != 1 || merged.Assignees[0] != "alice" {
+		t.Errorf("PR #1 Labels = %v, Milestone = %v, Assignees = %v", merged.Labels, merged.Milestone, merged.Assignees)
+	}
+	// 本文とclosingIssuesReferencesの重複はまとめる
+	if want := []IssueRef{{Repository: "owner/repo", Number: 10}, {Repository: "other/lib", Number: 3}}; !reflect.DeepEqual(merged.ClosingIssues, want) {
+		t.Errorf("PR #1 ClosingIssues = %v, want %v", merged.ClosingIssues, want)
+	}
+	if prs[1].State != "open" || prs[1].Merged {
+		t.Errorf("PR #2 State = %v, Merged = %v, want open, false", prs[1].State, prs[1].Merged)
+	}
+	// REST APIと同じくボットのユーザー名には[bot]が付く
+	if prs[1].Author != "dependabot[bot]" {
+		t.Errorf("PR #2 Author = %v, want dependabot[bot]", prs[1].Author)
+	}
+}
+
+func TestPRClient_SetBackend(t *testing.T) {
+	client := &PRClient{}
+	if err := client.SetBackend("graphql"); err != nil {
+		t.Errorf("SetBackend(graphql) error = %v", err)
+	}
+	if client.backend != BackendGraphQL {
+		t.Errorf("backend = %v, want graphql", client.backend)
+	}
+	if err := client.SetBackend("soap"); err == nil {
+		t.Error("SetBackend(soap) error = nil, want error")
+	}
+}
+
+func TestPRClient_FetchTodaysPRs_GraphQLTruncated(t *testing.T) {
+	now := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/1":
+			fmt.Fprint(w, `{"merged":true,"merged_at":"2024-01-01T00:00:00Z","base":{"ref":"main"}}`)
+		case "/repos/owner/repo/pulls/1/commits":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls/1/commits?per_page=100&page=2>; rel="next"`, serverURL))
+				fmt.Fprint(w, `[{"author":{"login":"other"},"commit":{"author":{"date":"2024-01-01T00:00:00Z"}}}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"author":{"login":"testuser"},"commit":{"author":{"date":"2024-02-04T03:00:00Z"}}}]`)
+		case "/repos/owner/repo/issues/1/events":
+			fmt.Fprint(w, `[]`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	client.backend = BackendGraphQL
+	client.gql = &mockGQLClient{t: t, handler: func(query string, variables map[string]interface{}) interface{} {
+		// 150件のコミットのうち直近100件だけが返され、期間内の自分のコミットは含まれない
+		node := graphQLNode(1, "MERGED", true)
+		node["author"] = map[string]interface{}{"login": "other", "__typename": "User"}
+		nodes := make([]interface{}, 0, 100)
+		for i := 0; i < 100; i++ {
+			nodes = append(nodes, map[string]interface{}{
+				"commit": map[string]interface{}{
+					"authoredDate": "2024-01-01T00:00:00Z",
+					"author":       map[string]interface{}{"user": map[string]interface{}{"login": "other"}},
+					"committer":    map[string]interface{}{"user": nil},
+				},
+			})
+		}
+		node["commits"] = map[string]interface{}{"totalCount": 150, "nodes": nodes}
+		return map[string]interface{}{
+			"viewer": map[string]interface{}{"login": "testuser"},
+			"search": map[string]interface{}{
+				"issueCount": 1,
+				"pageInfo":   map[string]interface{}{"hasNextPage": false},
+				"nodes":      []interface{}{node},
+			},
+		}
+	}}
+
+	prs, err := client.FetchTodaysPRs(SearchOptions{Range: Today(time.UTC)})
+	if err != nil {
+		t.Fatalf("FetchTodaysPRs() error = %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("FetchTodaysPRs() returned %d PRs, want 1", len(prs))
+	}
+	// 欠けたコミットはREST APIで取得し直して判定する
+	if prs[0].Contribution != ContributionCommitted {
+		t.Errorf("Contribution = %v, want committed", prs[0].Contribution)
+	}
+	if !prs[0].Merged || prs[0].Base != "main" {
+		t.Errorf("Merged = %v, Base = %v, want true, main", prs[0].Merged, prs[0].Base)
+	}
+}
+
+func TestPRClient_FetchTodaysPRs_GraphQLStatus(t *testing.T) {
+	now := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var restPaths []string
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		restPaths = append(restPaths, r.URL.Path)
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/def456/check-runs":
+			fmt.Fprint(w, `{"check_runs":[{"name":"e2e","status":"completed","conclusion":"failure"}]}`)
+		case "/repos/owner/repo/commits/def456/status":
+			fmt.Fprint(w, `{"statuses":[]}`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	review := func(login, state string) map[string]interface{} {
+		return map[string]interface{}{"state": state, "submittedAt": "2024-02-04T01:00:00Z", "author": map[string]interface{}{"login": login}}
+	}
+	client.backend = BackendGraphQL
+	client.gql = &mockGQLClient{t: t, handler: func(query string, variables map[string]interface{}) interface{} {
+		if variables["withChecks"] != true || variables["withReviewStatus"] != true {
+			t.Errorf("variables = %v, want withChecks and withReviewStatus", variables)
+		}
+		node := graphQLNode(1, "OPEN", false)
+		node["headRefOid"] = "abc123"
+		node["headCommit"] = map[string]interface{}{"nodes": []interface{}{map[string]interface{}{"commit": map[string]interface{}{
+			"statusCheckRollup": map[string]interface{}{"contexts": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": false},
+				"nodes": []interface{}{
+					map[string]interface{}{"__typename": "CheckRun", "name": "test", "status": "COMPLETED", "conclusion": "FAILURE"},
+					map[string]interface{}{"__typename": "StatusContext", "context": "ci/lint", "state": "SUCCESS"},
+				},
+			}},
+		}}}}
+		node["reviewRequests"] = map[string]interface{}{"totalCount": 2, "nodes": []interface{}{
+			map[string]interface{}{"requestedReviewer": map[string]interface{}{"__typename": "User", "login": "carol"}},
+			map[string]interface{}{"requestedReviewer": map[string]interface{}{"__typename": "Team", "slug": "backend"}},
+		}}
+		node["latestReviews"] = map[string]interface{}{"totalCount": 2, "nodes": []interface{}{review("alice", "COMMENTED"), review("bob", "COMMENTED")}}
+		node["latestOpinionatedReviews"] = map[string]interface{}{"totalCount": 1, "nodes": []interface{}{review("alice", "APPROVED")}}
+
+		// チェックが100件を超えるPRはCIの状態だけREST APIで取得し直す
+		truncated := graphQLNode(2, "OPEN", false)
+		truncated["headRefOid"] = "def456"
+		truncated["headCommit"] = map[string]interface{}{"nodes": []interface{}{map[string]interface{}{"commit": map[string]interface{}{
+			"statusCheckRollup": map[string]interface{}{"contexts": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": true},
+				"nodes":    []interface{}{},
+			}},
+		}}}}
+		truncated["reviewRequests"] = map[string]interface{}{"totalCount": 0, "nodes": []interface{}{}}
+		truncated["latestReviews"] = map[string]interface{}{"totalCount": 0, "nodes": []interface{}{}}
+		truncated["latestOpinionatedReviews"] = map[string]interface{}{"totalCount": 0, "nodes": []interface{}{}}
+
+		return map[string]interface{}{
+			"viewer": map[string]interface{}{"login": "testuser"},
+			"search": map[string]interface{}{
+				"issueCount": 2,
+				"pageInfo":   map[string]interface{}{"hasNextPage": false},
+				"nodes":      []interface{}{node, truncated},
+			},
+		}
+	}}
+
+	prs, err := client.FetchTodaysPRs(SearchOptions{Range: Today(time.UTC), WithChecks: true, WithReviewStatus: true})
+	if err != nil {
+		t.Fatalf("FetchTodaysPRs() error = %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("FetchTodaysPRs() returned %d PRs, want 2", len(prs))
+	}
+
+	pr := prs[0]
+	if pr.CheckState != CheckStateFailure || !reflect.DeepEqual(pr.FailingChecks, []string{"test"}) {
+		t.Errorf("PR #1 CheckState = %v, FailingChecks = %v, want failure, [test]", pr.CheckState, pr.FailingChecks)
+	}
+	if want := []string{"carol", "owner/backend"}; !reflect.DeepEqual(pr.RequestedReviewers, want) {
+		t.Errorf("PR #1 RequestedReviewers = %v, want %v", pr.RequestedReviewers, want)
+	}
+	if !reflect.DeepEqual(pr.Reviewers, []string{"alice", "bob"}) || !reflect.DeepEqual(pr.Approvers, []string{"alice"}) {
+		t.Errorf("PR #1 Reviewers = %v, Approvers = %v, want [alice bob], [alice]", pr.Reviewers, pr.Approvers)
+	}
+	if prs[1].CheckState != CheckStateFailure || !reflect.DeepEqual(prs[1].FailingChecks, []string{"e2e"}) {
+		t.Errorf("PR #2 CheckState = %v, FailingChecks = %v, want failure, [e2e]", prs[1].CheckState, prs[1].FailingChecks)
+	}
+	// PRの詳細とレビューはREST APIで取得しない
+	if want := []string{"/repos/owner/repo/commits/def456/check-runs", "/repos/owner/repo/commits/def456/status"}; !reflect.DeepEqual(restPaths, want) {
+		t.Errorf("REST requests = %v, want %v", restPaths, want)
+	}
+}
+
+func TestPRClient_FetchTodaysPRs_GraphQLErrors(t *testing.T) {
+	now := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		errors   []api.GQLErrorItem
+		wantPRs  int
+		wantErrs int
+		wantErr  bool
+	}{
+		{
+			name:    "検索結果が返されない場合は全体を失敗させる",
+			data:    nil,
+			errors:  []api.GQLErrorItem{{Type: "RATE_LIMITED", Message: "API rate limit exceeded"}},
+			wantErr: true,
+		},
+		{
+			name: "一部だけ返された場合は失敗したPRを記録して続ける",
+			data: map[string]interface{}{
+				"viewer": map[string]interface{}{"login": "testuser"},
+				"search": map[string]interface{}{
+					"issueCount": 2,
+					"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": "cursor1"},
+					"nodes":      []interface{}{graphQLNode(1, "MERGED", true), nil},
+				},
+			},
+			errors:   []api.GQLErrorItem{{Type: "FORBIDDEN", Message: "Resource protected by organization SAML enforcement."}},
+			wantPRs:  1,
+			wantErrs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &PRClient{
+				gql: &mockGQLClient{t: t, errors: tt.errors, handler: func(query string, variables map[string]interface{}) interface{} {
+					return tt.data
+				}},
+				backend:     BackendGraphQL,
+				commitCache: make(map[string][]prCommit),
+			}
+
+			prs, err := client.FetchTodaysPRs(SearchOptions{Range: Today(time.UTC)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchTodaysPRs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var gqlErr api.GQLError
+				if !errors.As(err, &gqlErr) {
+					t.Errorf("FetchTodaysPRs() error = %v, want GQLError", err)
+				}
+				return
+			}
+			if len(prs) != tt.wantPRs {
+				t.Errorf("FetchTodaysPRs() returned %d PRs, want %d", len(prs), tt.wantPRs)
+			}
+			if failures := client.Errors(); len(failures) != tt.wantErrs {
+				t.Errorf("Errors() returned %d failures, want %d", len(failures), tt.wantErrs)
+			}
+		})
+	}
+}
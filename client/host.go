@@ -0,0 +1,46 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli/go-gh/pkg/auth"
+)
+
+// DefaultHost はホストを指定しない場合に使うホスト（GH_HOSTかghの認証済みのホスト）を返す
+func DefaultHost() string {
+	host, _ := auth.DefaultHost()
+	return host
+}
+
+// isGitHubDotCom はホストがgithub.comかどうかを返す。www.github.comなども含む
+func isGitHubDotCom(host string) bool {
+	host = strings.ToLower(host)
+	return host == "github.com" || strings.HasSuffix(host, ".github.com")
+}
+
+// apiPrefix はホストのREST APIのURLの接頭辞を返す。
+// github.comはapi.github.com、GitHub Enterprise Serverは/api/v3/以下になる
+func apiPrefix(host string) string {
+	if host == "" || isGitHubDotCom(host) {
+		return "https://api.github.com/"
+	}
+	return fmt.Sprintf("https://%s/api/v3/", host)
+}
+
+// extractRepoFullName はREST APIのURLからowner/repoを取り出す。
+// GitHub Enterprise Serverの/api/v3/のような接頭辞があってもreposの後ろを使う
+func extractRepoFullName(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] == "repos" && parts[i+1] != "" && parts[i+2] != "" {
+			return parts[i+1] + "/" + parts[i+2]
+		}
+	}
+	return ""
+}
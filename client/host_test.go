@@ -0,0 +1,83 @@
+package client
+
+import "testing"
+
+func TestExtractRepoFullName(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiURL   string
+		expected string
+	}{
+		{
+			name:     "正常なURL",
+			apiURL:   "https://api.github.com/repos/owner/repo/issues/1",
+			expected: "owner/repo",
+		},
+		{
+			name:     "GitHub Enterprise ServerのURL",
+			apiURL:   "https://ghe.example.com/api/v3/repos/owner/repo/issues/1",
+			expected: "owner/repo",
+		},
+		{
+			name:     "reposの後ろが足りないURL",
+			apiURL:   "https://ghe.example.com/api/v3/repos/owner",
+			expected: "",
+		},
+		{
+			name:     "不正なURL",
+			apiURL:   "invalid-url",
+			expected: "",
+		},
+		{
+			name:     "空のURL",
+			apiURL:   "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractRepoFullName(tt.apiURL)
+			if got != tt.expected {
+				t.Errorf("extractRepoFullName() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAPIPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{
+			name:     "github.com",
+			host:     "github.com",
+			expected: "https://api.github.com/",
+		},
+		{
+			name:     "ホストの指定なし",
+			host:     "",
+			expected: "https://api.github.com/",
+		},
+		{
+			name:     "github.comのサブドメイン",
+			host:     "www.github.com",
+			expected: "https://api.github.com/",
+		},
+		{
+			name:     "GitHub Enterprise Server",
+			host:     "ghe.example.com",
+			expected: "https://ghe.example.com/api/v3/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiPrefix(tt.host); got != tt.expected {
+				t.Errorf("apiPrefix(%q) = %v, want %v", tt.host, got, tt.expected)
+			}
+		})
+	}
+}
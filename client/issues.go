@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Label はPRに付いたラベル
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// IssueRef はPRのマージでクローズされるIssue
+type IssueRef struct {
+	// owner/repo形式のリポジトリ名
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+}
+
+// String は"owner/repo#123"の形式で返す
+func (r IssueRef) String() string {
+	return fmt.Sprintf("%s#%d", r.Repository, r.Number)
+}
+
+// closingKeywordRE は"Closes #123"、"fixes owner/repo#123"、"Resolves https://github.com/owner/repo/issues/123"
+// のようなIssueをクローズするキーワードとその参照に一致する
+var closingKeywordRE = regexp.MustCompile(
+	`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(?:https?://[^/\s]+/([\w.-]+/[\w.-]+)/issues/|([\w.-]+/[\w.-]+)?#)(\d+)\b`)
+
+// parseClosingIssues はPRの本文からクローズするIssueを取り出す。
+// リポジトリを省略した参照はPRと同じリポジトリとする。
+func parseClosingIssues(body, repoFullName string) []IssueRef {
+	var refs []IssueRef
+	for _, m := range closingKeywordRE.FindAllStringSubmatch(body, -1) {
+		repo := repoFullName
+		if m[1] != "" {
+			repo = m[1]
+		} else if m[2] != "" {
+			repo = m[2]
+		}
+		number, err := strconv.Atoi(m[3])
+		if err != nil {
+			continue
+		}
+		refs = appendIssueRef(refs, IssueRef{Repository: repo, Number: number})
+	}
+	return refs
+}
+
+// appendIssueRef は重複しない場合だけ参照を追加する。
+// GitHubのowner/repoは大文字小文字を区別しないため、リポジトリ名は大文字小文字を無視して比べる。
+func appendIssueRef(refs []IssueRef, ref IssueRef) []IssueRef {
+	for _, r := range refs {
+		if strings.EqualFold(r.Repository, ref.Repository) && r.Number == ref.Number {
+			return refs
+		}
+	}
+	return append(refs, ref)
+}
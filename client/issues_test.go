@@ -0,0 +1,78 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseClosingIssues(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []IssueRef
+	}{
+		{name: "本文なし", body: "", expected: nil},
+		{
+			name:     "同じリポジトリのIssue",
+			body:     "Closes #12\nfixes: #34",
+			expected: []IssueRef{{Repository: "owner/repo", Number: 12}, {Repository: "owner/repo", Number: 34}},
+		},
+		{
+			name:     "別のリポジトリのIssueとURL",
+			body:     "Resolved other/lib#5 and resolves https://github.com/other/app/issues/7",
+			expected: []IssueRef{{Repository: "other/lib", Number: 5}, {Repository: "other/app", Number: 7}},
+		},
+		{
+			name:     "キーワードのない参照と重複は除く",
+			body:     "Related to #1. Fixed #2, FIXES #2",
+			expected: []IssueRef{{Repository: "owner/repo", Number: 2}},
+		},
+		{
+			name:     "大文字小文字だけが異なるリポジトリ名は重複とする",
+			body:     "Fixes MyOrg/Repo#5, fixes myorg/repo#5",
+			expected: []IssueRef{{Repository: "MyOrg/Repo", Number: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseClosingIssues(tt.body, "owner/repo")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseClosingIssues() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchItem_closingIssueRefs(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		closingIssues []IssueRef
+		expected      []IssueRef
+	}{
+		{name: "参照なし", expected: nil},
+		{
+			name:          "本文とGraphQLの参照をまとめる",
+			body:          "Fixes #1",
+			closingIssues: []IssueRef{{Repository: "owner/repo", Number: 1}, {Repository: "other/lib", Number: 3}},
+			expected:      []IssueRef{{Repository: "owner/repo", Number: 1}, {Repository: "other/lib", Number: 3}},
+		},
+		{
+			name:          "大文字小文字だけが異なる参照は本文の表記を残す",
+			body:          "Fixes MyOrg/Repo#5",
+			closingIssues: []IssueRef{{Repository: "myorg/repo", Number: 5}},
+			expected:      []IssueRef{{Repository: "MyOrg/Repo", Number: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := searchItem{Body: tt.body, closingIssues: tt.closingIssues}
+			got := item.closingIssueRefs("owner/repo")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("closingIssueRefs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reservedQualifiers はSearchOptionsから組み立てる検索の条件と、代わりに使うフラグ
+var reservedQualifiers = map[string]string{
+	"updated":     "--since/--until",
+	"author":      "--roleと--user",
+	"reviewed-by": "--roleと--user",
+	"commenter":   "--roleと--user",
+	"assignee":    "--roleと--user",
+	"involves":    "--roleと--user",
+	"org":         "--org",
+	"repo":        "--repo",
+	"user":        "--repo",
+}
+
+// ValidateQuery は追加の検索の条件が、期間や自分との関係などの組み立てる条件と重複しないかを検証する。
+// 否定の条件（-repo:など）は絞り込みになるため重複とはしない
+func ValidateQuery(query string) error {
+	for _, term := range splitQuery(query) {
+		if strings.HasPrefix(term, "-") {
+			continue
+		}
+		name, value, ok := strings.Cut(term, ":")
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(name)
+		if flag, ok := reservedQualifiers[name]; ok {
+			return fmt.Errorf("--queryに%sは指定できません（%sを使ってください）", term, flag)
+		}
+		value = strings.ToLower(strings.Trim(value, `"`))
+		if (name == "is" && value == "issue") || (name == "type" && value != "pr") {
+			return fmt.Errorf("--queryに%sは指定できません（PRだけを検索します）", term)
+		}
+	}
+	return nil
+}
+
+// splitQuery は検索の条件を空白で分ける。引用符で囲まれた空白では分けない
+func splitQuery(query string) []string {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			term.WriteRune(r)
+		case (r == ' ' || r == '\t' || r == '\n') && !quoted:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms
+}
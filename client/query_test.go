@@ -0,0 +1,66 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "追加の条件",
+			query: `is:unmerged language:go head:feature/ label:"good first issue"`,
+		},
+		{
+			name:  "否定の条件",
+			query: "-author:app/dependabot -repo:owner/sandbox",
+		},
+		{
+			name:  "PRの種類",
+			query: "type:pr is:pr",
+		},
+		{
+			name:    "期間",
+			query:   "language:go updated:>2024-01-01",
+			wantErr: "--since/--until",
+		},
+		{
+			name:    "自分との関係",
+			query:   "Author:alice",
+			wantErr: "--roleと--user",
+		},
+		{
+			name:    "リポジトリ",
+			query:   "repo:owner/repo",
+			wantErr: "--repo",
+		},
+		{
+			name:    "Issue",
+			query:   "is:issue",
+			wantErr: "PRだけを検索します",
+		},
+		{
+			name:  "引用符の中の空白",
+			query: `label:"author: me"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQuery(tt.query)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateQuery() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateQuery() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxConcurrency はPRの詳細を並列で取得するときの同時実行数の上限
+	DefaultMaxConcurrency = 10
+
+	maxRetries     = 3
+	retryBaseDelay = time.Second
+	// これより長く待つ必要がある場合はリトライせずに失敗させる
+	maxRetryWait = time.Minute
+	// 残りが上限のこの割合（%）を下回ったら同時実行数を増やさない
+	lowRateLimitPercent = 10
+)
+
+// retryTransport はレート制限やサーバーエラーのレスポンスを待機してリトライするRoundTripper。
+// 制限を受けた場合は同時実行数を減らす。
+type retryTransport struct {
+	base    http.RoundTripper
+	limiter *adaptiveLimiter
+	stats   *rateLimitStats
+	logf    func(format string, args ...interface{})
+	// テスト用に待機をモック可能にする
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.stats.record(resp)
+
+		throttled := isRateLimited(resp)
+		switch {
+		case throttled:
+			t.limiter.throttled()
+		case isRateLimitLow(resp):
+			// 実際に制限を受けるまでは減らさず、増やすのだけ止める
+		default:
+			t.limiter.succeeded()
+		}
+
+		if !throttled && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if attempt >= maxRetries {
+			return resp, nil
+		}
+
+		delay := retryDelay(resp, attempt)
+		if delay > maxRetryWait {
+			t.logf("レート制限の解除まで%sかかるためリトライしません: %s\n", delay.Round(time.Second), req.URL.Path)
+			return resp, nil
+		}
+
+		// 送信済みのボディは読み直す必要がある
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp.Body.Close()
+		t.stats.retried(throttled)
+		t.logf("リトライ (%d/%d): %s %d、%s後に再試行\n", attempt+1, maxRetries, req.URL.Path, resp.StatusCode, delay.Round(time.Millisecond))
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRateLimited はレスポンスがレート制限（セカンダリレート制限を含む）によるものか判定する
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return true
+		}
+		// セカンダリレート制限はボディのメッセージでしか判別できない
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return err == nil && strings.Contains(strings.ToLower(string(body)), "rate limit")
+	}
+	return false
+}
+
+// isRateLimitLow はレスポンスのリソース（core、search、graphqlなど）の残りが
+// そのリソースの上限に対して少なくなっているか判定する。
+// searchは1分あたり30回しかないため、残りの件数ではなく割合で比べる。
+func isRateLimitLow(resp *http.Response) bool {
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil || limit <= 0 {
+		return false
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return false
+	}
+	return remaining*100 < limit*lowRateLimitPercent
+}
+
+// retryDelay はRetry-After、X-RateLimit-Reset、指数バックオフの順に待機時間を決める
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			if d := time.Unix(reset, 0).Sub(timeNow()); d > 0 {
+				return d + time.Second
+			}
+		}
+	}
+	backoff := retryBaseDelay << attempt
+	// 同時に失敗したリクエストが一斉に再試行しないようにずらす
+	return backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// adaptiveLimiter は同時実行数を制限するセマフォ。
+// レート制限を受けると上限を半分にし、成功が続くと少しずつ戻す。
+type adaptiveLimiter struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	max       int
+	limit     int
+	inUse     int
+	successes int
+}
+
+func newAdaptiveLimiter(max int) *adaptiveLimiter {
+	if max < 1 {
+		max = 1
+	}
+	l := &adaptiveLimiter{max: max, limit: max}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *adaptiveLimiter) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inUse >= l.limit {
+		l.cond.Wait()
+	}
+	l.inUse++
+}
+
+func (l *adaptiveLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inUse--
+	l.cond.Broadcast()
+}
+
+func (l *adaptiveLimiter) throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.successes = 0
+	if l.limit > 1 {
+		l.limit /= 2
+	}
+}
+
+func (l *adaptiveLimiter) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit >= l.max {
+		return
+	}
+	l.successes++
+	if l.successes >= l.limit {
+		l.limit++
+		l.successes = 0
+		l.cond.Broadcast()
+	}
+}
+
+func (l *adaptiveLimiter) current() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// rateLimitStats はレスポンスヘッダーから読み取ったレート制限の状態を集計する
+type rateLimitStats struct {
+	mu        sync.Mutex
+	requests  int
+	retries   int
+	throttles int
+	resources map[string]rateLimitState
+}
+
+type rateLimitState struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+func newRateLimitStats() *rateLimitStats {
+	return &rateLimitStats{resources: make(map[string]rateLimitState)}
+}
+
+func (s *rateLimitStats) record(resp *http.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+	s.resources[resource] = rateLimitState{limit: limit, remaining: remaining, reset: time.Unix(reset, 0)}
+}
+
+func (s *rateLimitStats) retried(throttled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+	if throttled {
+		s.throttles++
+	}
+}
+
+func (s *rateLimitStats) summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "レート制限: リクエスト %d件、リトライ %d回（うちレート制限 %d回）\n", s.requests, s.retries, s.throttles)
+
+	resources := make([]string, 0, len(s.resources))
+	for resource := range s.resources {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	for _, resource := range resources {
+		state := s.resources[resource]
+		fmt.Fprintf(&b, "  %s: 残り %d/%d（リセット %s）\n", resource, state.remaining, state.limit, state.reset.Format("15:04:05"))
+	}
+	return b.String()
+}
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	type reply struct {
+		status int
+		header map[string]string
+		body   string
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		replies       []reply
+		wantStatus    int
+		wantRequests  int
+		wantDelays    []time.Duration
+		wantThrottled bool
+	}{
+		{
+			name:         "成功はそのまま返す",
+			replies:      []reply{{status: 200}},
+			wantStatus:   200,
+			wantRequests: 1,
+		},
+		{
+			name: "429はRetry-Afterだけ待ってリトライ",
+			replies: []reply{
+				{status: 429, header: map[string]string{"Retry-After": "3"}},
+				{status: 200},
+			},
+			wantStatus:    200,
+			wantRequests:  2,
+			wantDelays:    []time.Duration{3 * time.Second},
+			wantThrottled: true,
+		},
+		{
+			name: "セカンダリレート制限の403はリトライ",
+			replies: []reply{
+				{status: 403, body: `{"message":"You have exceeded a secondary rate limit."}`},
+				{status: 200},
+			},
+			wantStatus:    200,
+			wantRequests:  2,
+			wantThrottled: true,
+		},
+		{
+			name:         "権限がない403はリトライしない",
+			replies:      []reply{{status: 403, body: `{"message":"Resource protected by organization SAML enforcement."}`}},
+			wantStatus:   403,
+			wantRequests: 1,
+		},
+		{
+			name: "5xxは上限までリトライ",
+			replies: []reply{
+				{status: 502}, {status: 502}, {status: 502}, {status: 502},
+			},
+			wantStatus:   502,
+			wantRequests: 4,
+		},
+		{
+			name: "解除まで長すぎる場合はリトライしない",
+			replies: []reply{
+				{status: 403, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1707051600"}},
+			},
+			wantStatus:    403,
+			wantRequests:  1,
+			wantThrottled: true,
+		},
+		{
+			name: "searchの残りが少なくても上限に対する割合が十分なら減らさない",
+			replies: []reply{
+				{status: 200, header: map[string]string{"X-RateLimit-Resource": "search", "X-RateLimit-Limit": "30", "X-RateLimit-Remaining": "25"}},
+			},
+			wantStatus:   200,
+			wantRequests: 1,
+		},
+		{
+			name: "残りがわずかでも制限されるまでは減らさない",
+			replies: []reply{
+				{status: 200, header: map[string]string{"X-RateLimit-Resource": "core", "X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "10"}},
+			},
+			wantStatus:   200,
+			wantRequests: 1,
+		},
+		{
+			name:   "POSTはボディを送り直す",
+			method: http.MethodPost,
+			replies: []reply{
+				{status: 503},
+				{status: 200},
+			},
+			wantStatus:   200,
+			wantRequests: 2,
+		},
+	}
+
+	now := time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					if body, _ := io.ReadAll(r.Body); string(body) != `{"query":"q"}` {
+						t.Errorf("request body = %q", body)
+					}
+				}
+				rep := tt.replies[requests]
+				requests++
+				for k, v := range rep.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(rep.status)
+				io.WriteString(w, rep.body)
+			}))
+			defer server.Close()
+
+			var delays []time.Duration
+			limiter := newAdaptiveLimiter(8)
+			transport := &retryTransport{
+				base:    http.DefaultTransport,
+				limiter: limiter,
+				stats:   newRateLimitStats(),
+				logf:    func(string, ...interface{}) {},
+				sleep: func(ctx context.Context, d time.Duration) error {
+					delays = append(delays, d)
+					return nil
+				},
+			}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			var body io.Reader
+			if method == http.MethodPost {
+				body = strings.NewReader(`{"query":"q"}`)
+			}
+			req, _ := http.NewRequest(method, server.URL+"/repos/owner/repo/pulls/1", body)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			for i, want := range tt.wantDelays {
+				if i >= len(delays) || delays[i] != want {
+					t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+					break
+				}
+			}
+			if got := limiter.current() < 8; got != tt.wantThrottled {
+				t.Errorf("throttled = %v, want %v", got, tt.wantThrottled)
+			}
+		})
+	}
+}
+
+func TestRetryTransportLowRemaining(t *testing.T) {
+	tests := []struct {
+		name      string
+		resource  string
+		limit     string
+		remaining string
+		want      int
+	}{
+		{
+			name:      "searchの30件中25件残りなら成功として戻す",
+			resource:  "search",
+			limit:     "30",
+			remaining: "25",
+			want:      3,
+		},
+		{
+			name:      "coreの残りが1割を切ったら増やさない",
+			resource:  "core",
+			limit:     "5000",
+			remaining: "400",
+			want:      2,
+		},
+		{
+			name:      "searchの残りが1割を切ったら増やさない",
+			resource:  "search",
+			limit:     "30",
+			remaining: "2",
+			want:      2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Resource", tt.resource)
+				w.Header().Set("X-RateLimit-Limit", tt.limit)
+				w.Header().Set("X-RateLimit-Remaining", tt.remaining)
+			}))
+			defer server.Close()
+
+			limiter := newAdaptiveLimiter(8)
+			limiter.throttled()
+			limiter.throttled()
+			transport := &retryTransport{
+				base:    http.DefaultTransport,
+				limiter: limiter,
+				stats:   newRateLimitStats(),
+				logf:    func(string, ...interface{}) {},
+				sleep:   func(context.Context, time.Duration) error { return nil },
+			}
+
+			// 上限2のときは2回成功すると1つ戻る
+			for i := 0; i < 2; i++ {
+				req, _ := http.NewRequest(http.MethodGet, server.URL+"/search/issues", nil)
+				resp, err := transport.RoundTrip(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+			}
+			if got := limiter.current(); got != tt.want {
+				t.Errorf("limit = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiter(t *testing.T) {
+	l := newAdaptiveLimiter(8)
+
+	l.throttled()
+	l.throttled()
+	if got := l.current(); got != 2 {
+		t.Fatalf("limit after throttling = %d, want 2", got)
+	}
+
+	// 現在の上限と同じ回数だけ成功すると1つ戻す
+	l.succeeded()
+	l.succeeded()
+	if got := l.current(); got != 3 {
+		t.Errorf("limit after successes = %d, want 3", got)
+	}
+
+	for i := 0; i < 100; i++ {
+		l.succeeded()
+	}
+	if got := l.current(); got != 8 {
+		t.Errorf("limit = %d, want max 8", got)
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// hasWildcard はmyorg/service-*やrelease/*のようなワイルドカードを含む指定かどうかを返す
+func hasWildcard(repo string) bool {
+	return strings.ContainsAny(repo, "*?[")
+}
+
+// repoQualifiers はリポジトリの指定を検索の条件にする。
+// Search APIはワイルドカードに対応していないため、所有者がワイルドカードでなければuser:で所有者に絞り込み、
+// 残りはfilterReposで取り除く
+func repoQualifiers(repos []string) []string {
+	var qualifiers []string
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		qualifier := "repo:" + repo
+		if hasWildcard(repo) {
+			owner, _, _ := strings.Cut(repo, "/")
+			if hasWildcard(owner) {
+				continue
+			}
+			qualifier = "user:" + owner
+		}
+		if !seen[strings.ToLower(qualifier)] {
+			seen[strings.ToLower(qualifier)] = true
+			qualifiers = append(qualifiers, qualifier)
+		}
+	}
+	return qualifiers
+}
+
+// matchRepo はリポジトリ名がいずれかの指定に一致するかを大文字小文字を区別せずに返す
+func matchRepo(repos []string, fullName string) bool {
+	fullName = strings.ToLower(fullName)
+	for _, repo := range repos {
+		if ok, _ := path.Match(strings.ToLower(repo), fullName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filterRepos は検索のクエリでは絞り込めない条件に一致しない検索結果を、詳細を取得する前に取り除く。
+// ワイルドカードでリポジトリを指定した場合は一致しないリポジトリ（--orgを指定した場合はその組織を除く）、
+// ワイルドカードで除外したリポジトリ、ボットを除外する場合はボットが作成したPRを取り除く
+func filterRepos(items []searchItem, opts SearchOptions) []searchItem {
+	patterned := false
+	for _, repo := range opts.Repos {
+		patterned = patterned || hasWildcard(repo)
+	}
+	excludeRepos := opts.wildcardExcludeRepos()
+	if !patterned && len(excludeRepos) == 0 && !opts.ExcludeBots {
+		return items
+	}
+
+	var filtered []searchItem
+	for _, item := range items {
+		fullName := item.repoFullName()
+		owner, _, _ := strings.Cut(fullName, "/")
+		if patterned && !matchRepo(opts.Repos, fullName) && (opts.Org == "" || !strings.EqualFold(owner, opts.Org)) {
+			continue
+		}
+		if matchRepo(excludeRepos, fullName) {
+			continue
+		}
+		if opts.ExcludeBots && isBot(item.User.Login) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+// ValidateRepo はowner/repo形式（ワイルドカードを含んでもよい）のリポジトリの指定かどうかを検証する
+func ValidateRepo(repo string) error {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("不正なリポジトリ: %s（owner/repo形式で指定してください）", repo)
+	}
+	if _, err := path.Match(repo, ""); err != nil {
+		return fmt.Errorf("不正なリポジトリのパターン: %s", repo)
+	}
+	return nil
+}
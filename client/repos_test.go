@@ -0,0 +1,105 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterRepos(t *testing.T) {
+	items := []searchItem{
+		{Number: 1, URL: "https://api.github.com/repos/myorg/service-a/issues/1"},
+		{Number: 2, URL: "https://api.github.com/repos/MyOrg/Service-B/issues/2"},
+		{Number: 3, URL: "https://api.github.com/repos/myorg/web/issues/3"},
+		{Number: 4, URL: "https://api.github.com/repos/other/service-c/issues/4"},
+		{Number: 5, URL: "https://api.github.com/repos/myorg/tmp-test/issues/5"},
+	}
+	items[3].User.Login = "dependabot[bot]"
+	tests := []struct {
+		name     string
+		opts     SearchOptions
+		expected []int
+	}{
+		{
+			name:     "ワイルドカードなし",
+			opts:     SearchOptions{Repos: []string{"myorg/web"}},
+			expected: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "ワイルドカード指定",
+			opts:     SearchOptions{Repos: []string{"myorg/service-*"}},
+			expected: []int{1, 2},
+		},
+		{
+			name:     "ワイルドカードとリポジトリ指定",
+			opts:     SearchOptions{Repos: []string{"myorg/service-*", "other/service-c"}},
+			expected: []int{1, 2, 4},
+		},
+		{
+			name:     "組織も指定",
+			opts:     SearchOptions{Org: "other", Repos: []string{"myorg/service-*"}},
+			expected: []int{1, 2, 4},
+		},
+		{
+			name:     "ワイルドカードで除外するリポジトリ",
+			opts:     SearchOptions{Filters: Filters{ExcludeRepos: []string{"myorg/web", "myorg/tmp-*"}}},
+			expected: []int{1, 2, 3, 4},
+		},
+		{
+			name:     "ボットを除外",
+			opts:     SearchOptions{Filters: Filters{ExcludeBots: true}},
+			expected: []int{1, 2, 3, 5},
+		},
+		{
+			name:     "ワイルドカード指定と除外",
+			opts:     SearchOptions{Repos: []string{"myorg/*"}, Filters: Filters{ExcludeRepos: []string{"myorg/tmp-*"}}},
+			expected: []int{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, item := range filterRepos(items, tt.opts) {
+				got = append(got, item.Number)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("filterRepos() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    string
+		wantErr bool
+	}{
+		{
+			name: "owner/repo形式",
+			repo: "owner/repo",
+		},
+		{
+			name: "ワイルドカード",
+			repo: "myorg/service-*",
+		},
+		{
+			name:    "所有者なし",
+			repo:    "repo",
+			wantErr: true,
+		},
+		{
+			name:    "不正なパターン",
+			repo:    "myorg/service-[",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRepo(tt.repo); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRepo(%q) error = %v, wantErr %v", tt.repo, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Role は検索するPRと自分との関係
+type Role string
+
+const (
+	// RoleAuthor は自分が作成したPR
+	RoleAuthor Role = "author"
+	// RoleReviewer は自分がレビューしたPR
+	RoleReviewer Role = "reviewer"
+	// RoleCommenter は自分がコメントしたPR
+	RoleCommenter Role = "commenter"
+	// RoleAssignee は自分がアサインされたPR
+	RoleAssignee Role = "assignee"
+	// RoleInvolves は上記のいずれか、またはメンションされたPR
+	RoleInvolves Role = "involves"
+)
+
+// ParseRoles はフラグで指定された関係を検証する。
+// 重複は取り除き、何も指定されていない場合はauthorとする。
+func ParseRoles(values []string) ([]Role, error) {
+	var roles []Role
+	seen := make(map[Role]bool)
+	for _, value := range values {
+		role := Role(strings.TrimSpace(value))
+		switch role {
+		case RoleAuthor, RoleReviewer, RoleCommenter, RoleAssignee, RoleInvolves:
+		default:
+			return nil, fmt.Errorf("不明なロール: %s（author/reviewer/commenter/assignee/involvesのいずれかを指定してください）", value)
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		roles = []Role{RoleAuthor}
+	}
+	return roles, nil
+}
+
+// qualifier は検索クエリで使用する修飾子を返す。userが空の場合は認証ユーザーを対象にする
+func (r Role) qualifier(user string) string {
+	if user == "" {
+		user = "@me"
+	}
+	switch r {
+	case RoleReviewer:
+		return "reviewed-by:" + user
+	case RoleCommenter:
+		return "commenter:" + user
+	case RoleAssignee:
+		return "assignee:" + user
+	case RoleInvolves:
+		return "involves:" + user
+	default:
+		return "author:" + user
+	}
+}
+
+// needsReviews はレビューやコメントの情報が必要な関係かどうかを返す
+func (r Role) needsReviews() bool {
+	return r != RoleAuthor
+}
+
+// prReview はPRのレビューのうち判定に必要な情報
+type prReview struct {
+	AuthorLogin string
+	State       string
+	SubmittedAt time.Time
+}
+
+// prComment はPRへのコメント（レビューコメントを含む）のうち判定に必要な情報
+type prComment struct {
+	AuthorLogin string
+	CreatedAt   time.Time
+}
+
+// fetchReviews はPRのレビュー一覧を取得する
+func (c *PRClient) fetchReviews(repoFullName string, number int) ([]prReview, error) {
+	type reviewResponse struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State       string    `json:"state"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	}
+
+	reviewsPath := fmt.Sprintf("repos/%s/pulls/%d/reviews?per_page=100", repoFullName, number)
+	c.debugPrint("レビュー取得: %s\n", reviewsPath)
+
+	response, err := fetchAllPages[reviewResponse](c, reviewsPath)
+	if err != nil {
+		return nil, fmt.Errorf("レビュー情報の取得に失敗: %w", err)
+	}
+
+	reviews := make([]prReview, 0, len(response))
+	for _, review := range response {
+		reviews = append(reviews, prReview{
+			AuthorLogin: review.User.Login,
+			State:       review.State,
+			SubmittedAt: review.SubmittedAt,
+		})
+	}
+	return reviews, nil
+}
+
+// fetchComments はPRへのコメントとレビューコメントをまとめて取得する
+func (c *PRClient) fetchComments(repoFullName string, number int) ([]prComment, error) {
+	type commentResponse struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	var comments []prComment
+	for _, commentsPath := range []string{
+		fmt.Sprintf("repos/%s/issues/%d/comments?per_page=100", repoFullName, number),
+		fmt.Sprintf("repos/%s/pulls/%d/comments?per_page=100", repoFullName, number),
+	} {
+		c.debugPrint("コメント取得: %s\n", commentsPath)
+
+		response, err := fetchAllPages[commentResponse](c, commentsPath)
+		if err != nil {
+			return nil, fmt.Errorf("コメント情報の取得に失敗: %w", err)
+		}
+		for _, comment := range response {
+			comments = append(comments, prComment{
+				AuthorLogin: comment.User.Login,
+				CreatedAt:   comment.CreatedAt,
+			})
+		}
+	}
+	return comments, nil
+}
+
+// summarizeReviews は期間内の自分の最新のレビュー状態とコメント数を返す
+func summarizeReviews(details prDetails, username string, rng DateRange) (string, int) {
+	inRange := rng.Contains
+
+	state := ""
+	var latest time.Time
+	for _, review := range details.reviews {
+		if !strings.EqualFold(review.AuthorLogin, username) || !inRange(review.SubmittedAt) {
+			continue
+		}
+		// 保留中のレビューは提出日時がない
+		if review.State == "PENDING" {
+			continue
+		}
+		if state == "" || review.SubmittedAt.After(latest) {
+			state = review.State
+			latest = review.SubmittedAt
+		}
+	}
+
+	count := 0
+	for _, comment := range details.comments {
+		if strings.EqualFold(comment.AuthorLogin, username) && inRange(comment.CreatedAt) {
+			count++
+		}
+	}
+	return state, count
+}
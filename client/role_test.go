@@ -0,0 +1,148 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []Role
+		wantErr  bool
+	}{
+		{name: "未指定", values: nil, expected: []Role{RoleAuthor}},
+		{name: "複数指定", values: []string{"reviewer", "commenter"}, expected: []Role{RoleReviewer, RoleCommenter}},
+		{name: "重複", values: []string{"author", " author"}, expected: []Role{RoleAuthor}},
+		{name: "不明な関係", values: []string{"owner"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRoles(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("ParseRoles() = %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("ParseRoles() = %v, want %v", got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildSearchQuery_Roles(t *testing.T) {
+	rng, err := ParseDateRange("2024-01-01", "2024-01-31", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := SearchOptions{Range: rng}
+	tests := map[Role]string{
+		RoleAuthor:    "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z author:@me",
+		RoleReviewer:  "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z reviewed-by:@me",
+		RoleCommenter: "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z commenter:@me",
+		RoleAssignee:  "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z assignee:@me",
+		RoleInvolves:  "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z involves:@me",
+	}
+
+	for role, expected := range tests {
+		if got := buildSearchQuery(opts, role); got != expected {
+			t.Errorf("buildSearchQuery(%s) = %v, want %v", role, got, expected)
+		}
+	}
+
+	// 除外するリポジトリは否定の修飾子にする
+	opts.ExcludeRepos = []string{"owner/legacy", "owner/sandbox"}
+	expected := "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z author:@me -repo:owner/legacy -repo:owner/sandbox"
+	if got := buildSearchQuery(opts, RoleAuthor); got != expected {
+		t.Errorf("buildSearchQuery(exclude) = %v, want %v", got, expected)
+	}
+	opts.ExcludeRepos = nil
+
+	// 追加の条件はそのまま末尾に付ける
+	opts.Query = " is:unmerged language:go "
+	expected = "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z author:@me is:unmerged language:go"
+	if got := buildSearchQuery(opts, RoleAuthor); got != expected {
+		t.Errorf("buildSearchQuery(query) = %v, want %v", got, expected)
+	}
+	opts.Query = ""
+
+	// ユーザーを指定した場合は@meの代わりに使う
+	opts.User = "alice"
+	expected = "is:pr updated:2024-01-01T00:00:00Z..2024-01-31T23:59:59Z reviewed-by:alice"
+	if got := buildSearchQuery(opts, RoleReviewer); got != expected {
+		t.Errorf("buildSearchQuery(user) = %v, want %v", got, expected)
+	}
+}
+
+func TestSummarizeReviews(t *testing.T) {
+	sinceTime := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
+	untilTime := sinceTime.Add(24 * time.Hour)
+
+	details := prDetails{
+		reviews: []prReview{
+			{AuthorLogin: "me", State: "COMMENTED", SubmittedAt: sinceTime.Add(1 * time.Hour)},
+			{AuthorLogin: "me", State: "CHANGES_REQUESTED", SubmittedAt: sinceTime.Add(3 * time.Hour)},
+			{AuthorLogin: "other", State: "APPROVED", SubmittedAt: sinceTime.Add(4 * time.Hour)},
+			// 期間外のレビューは対象外
+			{AuthorLogin: "me", State: "APPROVED", SubmittedAt: untilTime.Add(time.Hour)},
+		},
+		comments: []prComment{
+			{AuthorLogin: "me", CreatedAt: sinceTime.Add(1 * time.Hour)},
+			{AuthorLogin: "me", CreatedAt: sinceTime.Add(2 * time.Hour)},
+			{AuthorLogin: "other", CreatedAt: sinceTime.Add(2 * time.Hour)},
+			{AuthorLogin: "me", CreatedAt: sinceTime.Add(-time.Hour)},
+		},
+	}
+
+	state, count := summarizeReviews(details, "me", DateRange{Since: sinceTime, Until: untilTime})
+	if state != "CHANGES_REQUESTED" {
+		t.Errorf("summarizeReviews() state = %v, want CHANGES_REQUESTED", state)
+	}
+	if count != 2 {
+		t.Errorf("summarizeReviews() count = %v, want 2", count)
+	}
+}
+
+func TestPRClient_fetchReviewsAndComments_Pagination(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		if got := r.URL.Query().Get("per_page"); got != "100" {
+			t.Errorf("per_page = %q, want 100", got)
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=2>; rel="next"`, serverURL, r.URL.Path))
+		}
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/1/reviews":
+			fmt.Fprint(w, `[{"user":{"login":"me"},"state":"COMMENTED","submitted_at":"2024-02-04T03:00:00Z"}]`)
+		case "/repos/owner/repo/issues/1/comments", "/repos/owner/repo/pulls/1/comments":
+			fmt.Fprint(w, `[{"user":{"login":"me"},"created_at":"2024-02-04T03:00:00Z"}]`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	reviews, err := client.fetchReviews("owner/repo", 1)
+	if err != nil {
+		t.Fatalf("fetchReviews() error = %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Errorf("fetchReviews() returned %d reviews, want 2", len(reviews))
+	}
+
+	comments, err := client.fetchComments("owner/repo", 1)
+	if err != nil {
+		t.Fatalf("fetchComments() error = %v", err)
+	}
+	if len(comments) != 4 {
+		t.Errorf("fetchComments() returned %d comments, want 4", len(comments))
+	}
+}
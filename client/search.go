@@ -0,0 +1,269 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// Search APIが1つのクエリに対して返す結果の上限
+const searchResultLimit = 1000
+
+// Linkヘッダーから次ページのURLを取り出す
+var nextLinkRE = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+type searchItem struct {
+	Title     string     `json:"title"`
+	URL       string     `json:"url"`
+	HTMLURL   string     `json:"html_url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	State     string     `json:"state"`
+	Draft     bool       `json:"draft"`
+	Number    int        `json:"number"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+	Body      string  `json:"body"`
+	Labels    []Label `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Assignees []searchUser `json:"assignees"`
+
+	// 検索に一致した関係
+	roles []Role
+
+	// 以下はGraphQLバックエンドでのみ設定される
+	merged         bool
+	reviewDecision string
+	baseRef        string
+	additions      int
+	deletions      int
+	changedFiles   int
+	commits        int
+	closingIssues  []IssueRef
+	details        prDetails
+	// コミットやレビューの一覧が途中までしか取得できていない
+	truncated bool
+	// オープンなPRのCIの状態とレビュー状況の判定に使う情報
+	status *pullStatus
+}
+
+type searchUser struct {
+	Login string `json:"login"`
+}
+
+type searchResponse struct {
+	Items []searchItem `json:"items"`
+	Total int          `json:"total_count"`
+}
+
+// milestone はマイルストーンのタイトルを返す。設定されていない場合は空文字列を返す
+func (item searchItem) milestone() string {
+	if item.Milestone == nil {
+		return ""
+	}
+	return item.Milestone.Title
+}
+
+// repoFullName はリポジトリ名を返す。REST APIの検索結果にはないためURLから取り出す
+func (item searchItem) repoFullName() string {
+	if item.Repository.FullName != "" {
+		return item.Repository.FullName
+	}
+	return extractRepoFullName(item.URL)
+}
+
+// assignees は担当者のユーザー名を返す
+func (item searchItem) assignees() []string {
+	var logins []string
+	for _, a := range item.Assignees {
+		logins = append(logins, a.Login)
+	}
+	return logins
+}
+
+// closingIssueRefs は本文と（GraphQLで取得した場合は）closingIssuesReferencesから
+// PRのマージでクローズされるIssueを返す
+func (item searchItem) closingIssueRefs(repoFullName string) []IssueRef {
+	refs := parseClosingIssues(item.Body, repoFullName)
+	for _, ref := range item.closingIssues {
+		refs = appendIssueRef(refs, ref)
+	}
+	return refs
+}
+
+// searchPageFunc は検索結果を1ページ取得し、次ページのカーソルを返す。
+// 最初のページはcursorを空文字列として呼び出す。
+type searchPageFunc func(query, cursor string) (response searchResponse, next string, err error)
+
+// searchPRs は関係ごとに検索結果を全ページ取得し、1つにまとめる。
+// 検索結果がSearch APIの上限を超える場合は期間を分割して検索し直す。
+func (c *PRClient) searchPRs(opts SearchOptions) ([]searchItem, error) {
+	var unique []searchItem
+	index := make(map[string]int)
+	for _, role := range opts.roles() {
+		items, err := c.searchWindow(opts, role)
+		if err != nil {
+			return nil, err
+		}
+
+		// 複数の関係に一致したPRや、期間を分割した場合に
+		// 検索の合間に更新されたPRは重複することがある
+		for _, item := range items {
+			i, ok := index[item.URL]
+			if !ok {
+				i = len(unique)
+				index[item.URL] = i
+				unique = append(unique, item)
+			}
+			if !containsRole(unique[i].roles, role) {
+				unique[i].roles = append(unique[i].roles, role)
+			}
+			if role.needsReviews() {
+				// レビュー情報を含む検索結果を優先する
+				unique[i].details.reviews = item.details.reviews
+				unique[i].details.comments = item.details.comments
+				unique[i].truncated = unique[i].truncated || item.truncated
+			}
+		}
+	}
+	return unique, nil
+}
+
+func containsRole(roles []Role, role Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *PRClient) searchWindow(opts SearchOptions, role Role) ([]searchItem, error) {
+	query := buildSearchQuery(opts, role)
+
+	fetchPage := c.fetchSearchPage
+	if c.backend == BackendGraphQL {
+		fetchPage = func(query, cursor string) (searchResponse, string, error) {
+			return c.fetchGraphQLSearchPage(query, cursor, role.needsReviews(), opts.WithChecks, opts.WithReviewStatus)
+		}
+	}
+
+	var items []searchItem
+	cursor := ""
+	for page := 1; page == 1 || cursor != ""; page++ {
+		response, next, err := fetchPage(query, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("PRの取得に失敗: %w", err)
+		}
+
+		if page == 1 {
+			c.debugPrint("検索結果: %d件\n", response.Total)
+
+			if response.Total > searchResultLimit {
+				if first, second, ok := opts.dateRange().split(); ok && !opts.OpenOnly {
+					c.debugPrint("検索結果が上限を超えたため期間を分割: %s, %s\n",
+						first.qualifier(), second.qualifier())
+
+					firstOpts, secondOpts := opts, opts
+					firstOpts.Range, secondOpts.Range = first, second
+
+					first, err := c.searchWindow(firstOpts, role)
+					if err != nil {
+						return nil, err
+					}
+					second, err := c.searchWindow(secondOpts, role)
+					if err != nil {
+						return nil, err
+					}
+					return append(first, second...), nil
+				}
+
+				c.warnf("検索結果が%d件あり、上限の%d件までしか取得できませんでした（%s）",
+					response.Total, searchResultLimit, query)
+			}
+		}
+
+		items = append(items, response.Items...)
+		cursor = next
+	}
+
+	return items, nil
+}
+
+// fetchSearchPage はREST APIで検索結果を1ページ取得する。
+// 次ページのカーソルにはLinkヘッダーのURLを使用する。
+func (c *PRClient) fetchSearchPage(query, cursor string) (searchResponse, string, error) {
+	var response searchResponse
+
+	path := cursor
+	if path == "" {
+		path = fmt.Sprintf("search/issues?%s", url.Values{
+			"q":        []string{query},
+			"sort":     []string{"updated"},
+			"order":    []string{"desc"},
+			"per_page": []string{"100"}, // 一度に取得するPR数を増やす
+		}.Encode())
+	}
+
+	// デバッグ出力
+	c.debugPrint("APIパス: %s\n", path)
+
+	resp, err := c.client.Request("GET", path, nil)
+	if err != nil {
+		return response, "", err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return response, "", fmt.Errorf("検索結果の解析に失敗: %w", err)
+	}
+
+	next := ""
+	if m := nextLinkRE.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+	return response, next, nil
+}
+
+// fetchAllPages は一覧を返すREST APIをLinkヘッダーの次ページがなくなるまで取得する。
+// pathにはper_pageを指定しておく。
+func fetchAllPages[T any](c *PRClient, path string) ([]T, error) {
+	return fetchAllPagesOf(c, path, func(page []T) []T { return page })
+}
+
+// fetchAllPagesOf はcheck-runsのように一覧をオブジェクトに含めて返すREST APIを、
+// Linkヘッダーの次ページがなくなるまで取得する。itemsでページから一覧を取り出す
+func fetchAllPagesOf[P, T any](c *PRClient, path string, items func(P) []T) ([]T, error) {
+	var all []T
+	for path != "" {
+		resp, err := c.client.Request("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page P
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("レスポンスの解析に失敗: %w", err)
+		}
+		all = append(all, items(page)...)
+
+		path = ""
+		if m := nextLinkRE.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			path = m[1]
+			c.debugPrint("次ページ取得: %s\n", path)
+		}
+	}
+	return all, nil
+}
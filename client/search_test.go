@@ -0,0 +1,169 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// テスト用にUTCで期間を作成する
+func utcRange(t *testing.T, since, until string) DateRange {
+	t.Helper()
+	rng, err := ParseDateRange(since, until, time.UTC)
+	if err != nil {
+		t.Fatalf("ParseDateRange() error = %v", err)
+	}
+	return rng
+}
+
+// 検索クエリごとのレスポンスを返すモックサーバー
+func setupSearchServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, serverURL string)) (*httptest.Server, *PRClient) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Logf("Received request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r, server.URL)
+	}))
+
+	return server, &PRClient{
+		client:      &mockRESTClient{baseURL: server.URL, t: t},
+		commitCache: make(map[string][]prCommit),
+	}
+}
+
+// pullDetailPathRE はPRの詳細のパスにマッチする（一覧と違い配列ではなくオブジェクトを返す）
+var pullDetailPathRE = regexp.MustCompile(`^/repos/[^/]+/[^/]+/pulls/\d+$`)
+
+func searchItems(start, count int) []searchItem {
+	items := make([]searchItem, 0, count)
+	for i := start; i < start+count; i++ {
+		items = append(items, searchItem{
+			Title:  fmt.Sprintf("PR %d", i),
+			URL:    fmt.Sprintf("https://api.github.com/repos/owner/repo/issues/%d", i),
+			State:  "open",
+			Number: i,
+		})
+	}
+	return items
+}
+
+func TestPRClient_searchPRs_Pagination(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/search/issues?page=2>; rel="next", <%s/search/issues?page=2>; rel="last"`, serverURL, serverURL))
+			json.NewEncoder(w).Encode(searchResponse{Items: searchItems(1, 100), Total: 150})
+		case "2":
+			json.NewEncoder(w).Encode(searchResponse{Items: searchItems(101, 50), Total: 150})
+		default:
+			t.Errorf("Unexpected page %s", r.URL.Query().Get("page"))
+		}
+	})
+	defer server.Close()
+
+	items, err := client.searchPRs(SearchOptions{Range: utcRange(t, "2024-01-01", "2024-01-31")})
+	if err != nil {
+		t.Fatalf("searchPRs() error = %v", err)
+	}
+	if len(items) != 150 {
+		t.Errorf("searchPRs() returned %d items, want 150", len(items))
+	}
+	if len(client.Warnings()) != 0 {
+		t.Errorf("Warnings() = %v, want none", client.Warnings())
+	}
+}
+
+func TestPRClient_searchPRs_SplitsLargeRange(t *testing.T) {
+	var queries []string
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		q := r.URL.Query().Get("q")
+		queries = append(queries, q)
+		switch q {
+		case "is:pr updated:2024-01-01T00:00:00Z..2024-01-04T23:59:59Z author:@me":
+			json.NewEncoder(w).Encode(searchResponse{Items: searchItems(1, 100), Total: 1500})
+		case "is:pr updated:2024-01-01T00:00:00Z..2024-01-02T23:59:59Z author:@me":
+			json.NewEncoder(w).Encode(searchResponse{Items: searchItems(1, 2), Total: 2})
+		case "is:pr updated:2024-01-03T00:00:00Z..2024-01-04T23:59:59Z author:@me":
+			json.NewEncoder(w).Encode(searchResponse{Items: searchItems(2, 2), Total: 2})
+		default:
+			t.Errorf("Unexpected query %s", q)
+		}
+	})
+	defer server.Close()
+
+	items, err := client.searchPRs(SearchOptions{Range: utcRange(t, "2024-01-01", "2024-01-04")})
+	if err != nil {
+		t.Fatalf("searchPRs() error = %v", err)
+	}
+	if len(queries) != 3 {
+		t.Errorf("search requests = %v, want 3 requests", queries)
+	}
+	// 分割した期間で重複したPRは1件にまとめる
+	if len(items) != 3 {
+		t.Errorf("searchPRs() returned %d items, want 3", len(items))
+	}
+}
+
+func TestPRClient_searchPRs_WarnsWhenCapped(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		json.NewEncoder(w).Encode(searchResponse{Items: searchItems(1, 100), Total: 1200})
+	})
+	defer server.Close()
+
+	items, err := client.searchPRs(SearchOptions{Range: DateRange{Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC)}})
+	if err != nil {
+		t.Fatalf("searchPRs() error = %v", err)
+	}
+	if len(items) != 100 {
+		t.Errorf("searchPRs() returned %d items, want 100", len(items))
+	}
+	if len(client.Warnings()) != 1 {
+		t.Errorf("Warnings() = %v, want 1 warning", client.Warnings())
+	}
+}
+
+func TestPRClient_searchPRs_MergesRoles(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		switch r.URL.Query().Get("q") {
+		case "is:pr updated:2024-01-01T00:00:00Z..2024-01-02T23:59:59Z reviewed-by:@me":
+			json.NewEncoder(w).Encode(searchResponse{Items: searchItems(1, 2), Total: 2})
+		case "is:pr updated:2024-01-01T00:00:00Z..2024-01-02T23:59:59Z commenter:@me":
+			json.NewEncoder(w).Encode(searchResponse{Items: searchItems(2, 2), Total: 2})
+		default:
+			t.Errorf("Unexpected query %s", r.URL.Query().Get("q"))
+		}
+	})
+	defer server.Close()
+
+	items, err := client.searchPRs(SearchOptions{
+		Range: utcRange(t, "2024-01-01", "2024-01-02"),
+		Roles: []Role{RoleReviewer, RoleCommenter},
+	})
+	if err != nil {
+		t.Fatalf("searchPRs() error = %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("searchPRs() returned %d items, want 3", len(items))
+	}
+
+	expected := [][]Role{
+		{RoleReviewer},
+		{RoleReviewer, RoleCommenter},
+		{RoleCommenter},
+	}
+	for i, item := range items {
+		if len(item.roles) != len(expected[i]) {
+			t.Errorf("items[%d].roles = %v, want %v", i, item.roles, expected[i])
+			continue
+		}
+		for j := range item.roles {
+			if item.roles[j] != expected[i][j] {
+				t.Errorf("items[%d].roles = %v, want %v", i, item.roles, expected[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CIの状態
+const (
+	CheckStateSuccess = "success"
+	CheckStateFailure = "failure"
+	CheckStatePending = "pending"
+)
+
+// fetchStatuses はオープンなPRのCIの状態とレビュー状況を並列で取得する。
+// 取得に失敗したPRはステータスなしのまま残す。
+func (c *PRClient) fetchStatuses(prs []PullRequest, withChecks, withReviews bool) error {
+	var wg sync.WaitGroup
+	errChan := make(chan PRError, len(prs))
+	limiter := c.concurrency() // 同時実行数を制限
+
+	for i := range prs {
+		if prs[i].State != "open" {
+			continue
+		}
+		wg.Add(1)
+		go func(pr *PullRequest) {
+			defer wg.Done()
+			limiter.acquire()
+			defer limiter.release()
+
+			if err := c.fetchStatus(pr, withChecks, withReviews); err != nil {
+				c.debugPrint("ステータスの取得に失敗: %v\n", err)
+				errChan <- newPRError(pr.Repository.FullName, pr.Number, pr.HTMLURL, err)
+			}
+		}(&prs[i])
+	}
+	wg.Wait()
+	close(errChan)
+
+	var failures []PRError
+	for err := range errChan {
+		failures = append(failures, err)
+	}
+	return c.collectFailures(failures)
+}
+
+// requestedTeam はレビューを依頼されたチーム
+type requestedTeam struct {
+	Slug string `json:"slug"`
+}
+
+// pullStatus はPRの詳細のうちCIの状態とレビュー状況の判定に使う部分
+type pullStatus struct {
+	Head struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+	RequestedReviewers []searchUser    `json:"requested_reviewers"`
+	RequestedTeams     []requestedTeam `json:"requested_teams"`
+
+	// 取得済みのレビュー（hasReviewsがfalseの場合は未取得）
+	reviews    []prReview
+	hasReviews bool
+	// GraphQLで取得済みのCIの状態（hasChecksがfalseの場合は未取得）
+	checkState    string
+	failingChecks []string
+	hasChecks     bool
+}
+
+// fetchStatus はPRのheadのCIの状態（withChecks）と、
+// レビュー依頼・レビュー結果（withReviews）を取得する。
+// detectContributionやGraphQLの検索で取得済みのPRの詳細、レビュー、CIの状態があれば使い回す。
+func (c *PRClient) fetchStatus(pr *PullRequest, withChecks, withReviews bool) error {
+	status := pr.status
+	if status == nil {
+		status = &pullStatus{}
+		prPath := fmt.Sprintf("repos/%s/pulls/%d", pr.Repository.FullName, pr.Number)
+		c.debugPrint("ステータス取得: %s\n", prPath)
+		if err := c.client.Get(prPath, status); err != nil {
+			return fmt.Errorf("PRの詳細の取得に失敗: %w", err)
+		}
+	}
+
+	if withChecks && status.hasChecks {
+		pr.CheckState, pr.FailingChecks = status.checkState, status.failingChecks
+	} else if withChecks && status.Head.SHA != "" {
+		var err error
+		pr.CheckState, pr.FailingChecks, err = c.fetchCheckState(pr.Repository.FullName, status.Head.SHA)
+		if err != nil {
+			return err
+		}
+	}
+	if !withReviews {
+		return nil
+	}
+
+	pr.RequestedReviewers = nil
+	for _, reviewer := range status.RequestedReviewers {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, reviewer.Login)
+	}
+	for _, team := range status.RequestedTeams {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, repoOwner(pr.Repository.FullName)+"/"+team.Slug)
+	}
+
+	reviews := status.reviews
+	if !status.hasReviews {
+		var err error
+		reviews, err = c.fetchReviews(pr.Repository.FullName, pr.Number)
+		if err != nil {
+			return err
+		}
+	}
+	decision, reviewers, approvers := reviewDecision(reviews, pr.Author)
+	pr.Reviewers = reviewers
+	pr.Approvers = approvers
+	// GraphQLで取得済みの場合はブランチ保護の設定を反映した値を優先する
+	if pr.ReviewDecision == "" {
+		pr.ReviewDecision = decision
+	}
+	return nil
+}
+
+// repoOwner はowner/repo形式のリポジトリ名からオーナーを取り出す
+func repoOwner(repoFullName string) string {
+	owner, _, _ := strings.Cut(repoFullName, "/")
+	return owner
+}
+
+// reviewDecision はレビュー結果からPR全体のレビュー状態と、レビューした人、承認した人を求める。
+// 各レビュアーの最新のレビュー（コメントのみのものを除く）を採用する。
+func reviewDecision(reviews []prReview, author string) (string, []string, []string) {
+	type latestReview struct {
+		state string
+		at    time.Time
+	}
+	latest := make(map[string]latestReview)
+	var reviewers []string
+	for _, review := range reviews {
+		if review.AuthorLogin == "" || strings.EqualFold(review.AuthorLogin, author) {
+			continue
+		}
+		if _, ok := latest[review.AuthorLogin]; !ok {
+			reviewers = append(reviewers, review.AuthorLogin)
+			latest[review.AuthorLogin] = latestReview{}
+		}
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			if review.SubmittedAt.After(latest[review.AuthorLogin].at) {
+				latest[review.AuthorLogin] = latestReview{state: review.State, at: review.SubmittedAt}
+			}
+		}
+	}
+	sort.Strings(reviewers)
+
+	decision := "REVIEW_REQUIRED"
+	var approvers []string
+	for _, reviewer := range reviewers {
+		switch latest[reviewer].state {
+		case "CHANGES_REQUESTED":
+			decision = "CHANGES_REQUESTED"
+		case "APPROVED":
+			if decision != "CHANGES_REQUESTED" {
+				decision = "APPROVED"
+			}
+			approvers = append(approvers, reviewer)
+		}
+	}
+	return decision, reviewers, approvers
+}
+
+// checkRun はコミットのチェック実行のうち判定に必要な情報（状態と結論は小文字）
+type checkRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// commitStatus はコミットステータスのうち判定に必要な情報（状態は小文字）
+type commitStatus struct {
+	Context string `json:"context"`
+	State   string `json:"state"`
+}
+
+// fetchCheckState はコミットのチェック実行とコミットステータスをまとめた状態と、
+// 失敗したチェックの名前を返す。チェックが1つもない場合は空文字列を返す。
+func (c *PRClient) fetchCheckState(repoFullName, sha string) (string, []string, error) {
+	type checkRunsPage struct {
+		CheckRuns []checkRun `json:"check_runs"`
+	}
+	// 失敗したチェックが2ページ目以降にあっても見逃さないように全ページ取得する
+	checksPath := fmt.Sprintf("repos/%s/commits/%s/check-runs?per_page=100", repoFullName, sha)
+	c.debugPrint("チェック取得: %s\n", checksPath)
+	checkRuns, err := fetchAllPagesOf(c, checksPath, func(page checkRunsPage) []checkRun { return page.CheckRuns })
+	if err != nil {
+		return "", nil, fmt.Errorf("チェックの取得に失敗: %w", err)
+	}
+
+	type combinedStatusPage struct {
+		Statuses []commitStatus `json:"statuses"`
+	}
+	statusPath := fmt.Sprintf("repos/%s/commits/%s/status?per_page=100", repoFullName, sha)
+	c.debugPrint("コミットステータス取得: %s\n", statusPath)
+	statuses, err := fetchAllPagesOf(c, statusPath, func(page combinedStatusPage) []commitStatus { return page.Statuses })
+	if err != nil {
+		return "", nil, fmt.Errorf("コミットステータスの取得に失敗: %w", err)
+	}
+
+	state, failing := summarizeChecks(checkRuns, statuses)
+	return state, failing, nil
+}
+
+// summarizeChecks はチェック実行とコミットステータスをまとめた状態と、失敗したチェックの名前を返す
+func summarizeChecks(checkRuns []checkRun, statuses []commitStatus) (string, []string) {
+	var states, failing []string
+	fail := func(name string) {
+		states = append(states, CheckStateFailure)
+		failing = append(failing, name)
+	}
+	for _, run := range checkRuns {
+		if run.Status != "completed" {
+			states = append(states, CheckStatePending)
+			continue
+		}
+		switch run.Conclusion {
+		case "failure", "timed_out", "cancelled", "action_required", "startup_failure":
+			fail(run.Name)
+		default:
+			states = append(states, CheckStateSuccess)
+		}
+	}
+	for _, s := range statuses {
+		switch s.State {
+		case "failure", "error":
+			fail(s.Context)
+		case "pending", "expected":
+			states = append(states, CheckStatePending)
+		default:
+			states = append(states, CheckStateSuccess)
+		}
+	}
+	sort.Strings(failing)
+	return combineCheckStates(states), failing
+}
+
+// combineCheckStates は個々のチェックの状態を1つにまとめる。
+// 失敗が1つでもあれば失敗、実行中があれば実行中とする。
+func combineCheckStates(states []string) string {
+	combined := ""
+	for _, state := range states {
+		switch state {
+		case CheckStateFailure:
+			return CheckStateFailure
+		case CheckStatePending:
+			combined = CheckStatePending
+		case CheckStateSuccess:
+			if combined == "" {
+				combined = CheckStateSuccess
+			}
+		}
+	}
+	return combined
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReviewDecision(t *testing.T) {
+	base := time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		reviews   []prReview
+		decision  string
+		reviewers []string
+		approvers []string
+	}{
+		{
+			name:      "レビューなし",
+			decision:  "REVIEW_REQUIRED",
+			reviewers: nil,
+		},
+		{
+			name: "承認後に別の人が変更依頼",
+			reviews: []prReview{
+				{AuthorLogin: "bob", State: "APPROVED", SubmittedAt: base},
+				{AuthorLogin: "alice", State: "CHANGES_REQUESTED", SubmittedAt: base.Add(time.Hour)},
+			},
+			decision:  "CHANGES_REQUESTED",
+			reviewers: []string{"alice", "bob"},
+			approvers: []string{"bob"},
+		},
+		{
+			name: "変更依頼の後に承認",
+			reviews: []prReview{
+				{AuthorLogin: "alice", State: "CHANGES_REQUESTED", SubmittedAt: base},
+				{AuthorLogin: "alice", State: "COMMENTED", SubmittedAt: base.Add(time.Hour)},
+				{AuthorLogin: "alice", State: "APPROVED", SubmittedAt: base.Add(2 * time.Hour)},
+			},
+			decision:  "APPROVED",
+			reviewers: []string{"alice"},
+			approvers: []string{"alice"},
+		},
+		{
+			name: "作成者自身のコメントは除外",
+			reviews: []prReview{
+				{AuthorLogin: "me", State: "COMMENTED", SubmittedAt: base},
+			},
+			decision:  "REVIEW_REQUIRED",
+			reviewers: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, reviewers, approvers := reviewDecision(tt.reviews, "me")
+			if decision != tt.decision {
+				t.Errorf("reviewDecision() decision = %v, want %v", decision, tt.decision)
+			}
+			if len(reviewers) != len(tt.reviewers) {
+				t.Fatalf("reviewDecision() reviewers = %v, want %v", reviewers, tt.reviewers)
+			}
+			for i := range reviewers {
+				if reviewers[i] != tt.reviewers[i] {
+					t.Errorf("reviewDecision() reviewers = %v, want %v", reviewers, tt.reviewers)
+				}
+			}
+			if !reflect.DeepEqual(approvers, tt.approvers) {
+				t.Errorf("reviewDecision() approvers = %v, want %v", approvers, tt.approvers)
+			}
+		})
+	}
+}
+
+func TestCombineCheckStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		states   []string
+		expected string
+	}{
+		{name: "チェックなし", states: nil, expected: ""},
+		{name: "すべて成功", states: []string{"success", "success"}, expected: "success"},
+		{name: "実行中あり", states: []string{"success", "pending"}, expected: "pending"},
+		{name: "失敗あり", states: []string{"pending", "failure", "success"}, expected: "failure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := combineCheckStates(tt.states); got != tt.expected {
+				t.Errorf("combineCheckStates() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPRClient_fetchStatus(t *testing.T) {
+	responses := map[string]interface{}{
+		"/repos/owner/repo/pulls/1": map[string]interface{}{
+			"head":                map[string]interface{}{"sha": "abc123"},
+			"requested_reviewers": []interface{}{map[string]interface{}{"login": "carol"}},
+			"requested_teams":     []interface{}{map[string]interface{}{"slug": "backend"}},
+		},
+		"/repos/owner/repo/pulls/1/reviews?per_page=100": []interface{}{
+			map[string]interface{}{"user": map[string]interface{}{"login": "alice"}, "state": "APPROVED", "submitted_at": "2024-02-04T01:00:00Z"},
+		},
+		"/repos/owner/repo/commits/abc123/check-runs?per_page=100": map[string]interface{}{
+			"check_runs": []interface{}{
+				map[string]interface{}{"name": "test", "status": "completed", "conclusion": "failure"},
+				map[string]interface{}{"name": "lint", "status": "completed", "conclusion": "success"},
+			},
+		},
+		"/repos/owner/repo/commits/abc123/status?per_page=100": map[string]interface{}{
+			"state": "failure",
+			"statuses": []interface{}{
+				map[string]interface{}{"context": "ci/deploy", "state": "error"},
+			},
+		},
+	}
+
+	server, client := setupMockServer(t, responses)
+	defer server.Close()
+
+	pr := PullRequest{Number: 1, State: "open", Author: "me"}
+	pr.Repository.FullName = "owner/repo"
+	if err := client.fetchStatus(&pr, true, true); err != nil {
+		t.Fatalf("fetchStatus() error = %v", err)
+	}
+
+	if pr.ReviewDecision != "APPROVED" {
+		t.Errorf("PR.ReviewDecision = %v, want APPROVED", pr.ReviewDecision)
+	}
+	if len(pr.RequestedReviewers) != 2 || pr.RequestedReviewers[0] != "carol" || pr.RequestedReviewers[1] != "owner/backend" {
+		t.Errorf("PR.RequestedReviewers = %v, want [carol owner/backend]", pr.RequestedReviewers)
+	}
+	if len(pr.Reviewers) != 1 || pr.Reviewers[0] != "alice" {
+		t.Errorf("PR.Reviewers = %v, want [alice]", pr.Reviewers)
+	}
+	if len(pr.Approvers) != 1 || pr.Approvers[0] != "alice" {
+		t.Errorf("PR.Approvers = %v, want [alice]", pr.Approvers)
+	}
+	if pr.CheckState != CheckStateFailure {
+		t.Errorf("PR.CheckState = %v, want failure", pr.CheckState)
+	}
+	if len(pr.FailingChecks) != 2 || pr.FailingChecks[0] != "ci/deploy" || pr.FailingChecks[1] != "test" {
+		t.Errorf("PR.FailingChecks = %v, want [ci/deploy test]", pr.FailingChecks)
+	}
+}
+
+func TestPRClient_fetchStatus_ChecksOnly(t *testing.T) {
+	// レビュー情報は取得しない（モックにないパスを呼ぶとエラーになる）
+	responses := map[string]interface{}{
+		"/repos/owner/repo/pulls/1": map[string]interface{}{
+			"head": map[string]interface{}{"sha": "abc123"},
+		},
+		"/repos/owner/repo/commits/abc123/check-runs?per_page=100": map[string]interface{}{
+			"check_runs": []interface{}{
+				map[string]interface{}{"name": "test", "status": "in_progress"},
+			},
+		},
+		"/repos/owner/repo/commits/abc123/status?per_page=100": map[string]interface{}{
+			"state":    "pending",
+			"statuses": []interface{}{},
+		},
+	}
+
+	server, client := setupMockServer(t, responses)
+	defer server.Close()
+
+	pr := PullRequest{Number: 1, State: "open", Author: "me"}
+	pr.Repository.FullName = "owner/repo"
+	if err := client.fetchStatus(&pr, true, false); err != nil {
+		t.Fatalf("fetchStatus() error = %v", err)
+	}
+	if pr.CheckState != CheckStatePending || len(pr.FailingChecks) != 0 {
+		t.Errorf("PR.CheckState = %v, FailingChecks = %v, want pending and none", pr.CheckState, pr.FailingChecks)
+	}
+	if pr.ReviewDecision != "" || pr.Reviewers != nil {
+		t.Errorf("PR.ReviewDecision = %v, Reviewers = %v, want empty", pr.ReviewDecision, pr.Reviewers)
+	}
+}
+
+func TestPRClient_FetchTodaysPRs_ReusesDetailsForStatus(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/search/issues":
+			items := searchItems(1, 1)
+			items[0].User.Login = "bob"
+			json.NewEncoder(w).Encode(searchResponse{Items: items, Total: 1})
+		case "/repos/owner/repo/pulls/1":
+			fmt.Fprint(w, `{"head":{"sha":"abc123"},"requested_reviewers":[{"login":"carol"}]}`)
+		case "/repos/owner/repo/pulls/1/reviews":
+			fmt.Fprint(w, `[{"user":{"login":"me"},"state":"APPROVED","submitted_at":"2024-01-10T01:00:00Z"}]`)
+		case "/repos/owner/repo/commits/abc123/check-runs":
+			fmt.Fprint(w, `{"check_runs":[{"name":"test","status":"completed","conclusion":"success"}]}`)
+		case "/repos/owner/repo/commits/abc123/status":
+			fmt.Fprint(w, `{"statuses":[]}`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	})
+	defer server.Close()
+
+	prs, err := client.FetchTodaysPRs(SearchOptions{
+		Range:            utcRange(t, "2024-01-01", "2024-01-31"),
+		User:             "me",
+		Roles:            []Role{RoleReviewer},
+		WithChecks:       true,
+		WithReviewStatus: true,
+	})
+	if err != nil {
+		t.Fatalf("FetchTodaysPRs() error = %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("FetchTodaysPRs() returned %d PRs, want 1", len(prs))
+	}
+	pr := prs[0]
+	if pr.ReviewDecision != "APPROVED" || pr.CheckState != CheckStateSuccess || !reflect.DeepEqual(pr.RequestedReviewers, []string{"carol"}) {
+		t.Errorf("PR status = %v, %v, %v, want APPROVED, success, [carol]", pr.ReviewDecision, pr.CheckState, pr.RequestedReviewers)
+	}
+
+	// 関わり方の判定で取得したPRの詳細とレビューを使い回す
+	for _, path := range []string{"/repos/owner/repo/pulls/1", "/repos/owner/repo/pulls/1/reviews"} {
+		if requests[path] != 1 {
+			t.Errorf("requests to %s = %d, want 1", path, requests[path])
+		}
+	}
+}
+
+func TestPRClient_fetchCheckState_Pagination(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		if got := r.URL.Query().Get("per_page"); got != "100" {
+			t.Errorf("per_page = %q, want 100", got)
+		}
+		secondPage := r.URL.Query().Get("page") == "2"
+		if !secondPage {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=2>; rel="next"`, serverURL, r.URL.Path))
+		}
+		switch {
+		case r.URL.Path == "/repos/owner/repo/commits/abc123/check-runs" && !secondPage:
+			fmt.Fprint(w, `{"check_runs":[{"name":"lint","status":"completed","conclusion":"success"}]}`)
+		case r.URL.Path == "/repos/owner/repo/commits/abc123/check-runs":
+			fmt.Fprint(w, `{"check_runs":[{"name":"test","status":"completed","conclusion":"failure"}]}`)
+		case r.URL.Path == "/repos/owner/repo/commits/abc123/status" && !secondPage:
+			fmt.Fprint(w, `{"state":"failure","statuses":[{"context":"ci/build","state":"success"}]}`)
+		case r.URL.Path == "/repos/owner/repo/commits/abc123/status":
+			fmt.Fprint(w, `{"state":"failure","statuses":[{"context":"ci/deploy","state":"error"}]}`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	state, failing, err := client.fetchCheckState("owner/repo", "abc123")
+	if err != nil {
+		t.Fatalf("fetchCheckState() error = %v", err)
+	}
+	// 2ページ目の失敗も見逃さない
+	if state != CheckStateFailure {
+		t.Errorf("fetchCheckState() state = %v, want failure", state)
+	}
+	if want := []string{"ci/deploy", "test"}; !reflect.DeepEqual(failing, want) {
+		t.Errorf("fetchCheckState() failing = %v, want %v", failing, want)
+	}
+}
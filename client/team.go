@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// 複数のユーザーのPRを同時に検索する数の上限。
+// PRの詳細の取得は共通のadaptiveLimiterで制限されるため、ここでは検索の並列数だけを抑える
+const maxUserConcurrency = 4
+
+// FetchTeamMembers はチームのメンバーのユーザー名を取得する
+func (c *PRClient) FetchTeamMembers(org, slug string) ([]string, error) {
+	var members []string
+	path := fmt.Sprintf("orgs/%s/teams/%s/members?per_page=100", org, slug)
+	for path != "" {
+		c.debugPrint("チームメンバー取得: %s\n", path)
+
+		resp, err := c.client.Request("GET", path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("チームメンバーの取得に失敗: %w", err)
+		}
+
+		var response []struct {
+			Login string `json:"login"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("チームメンバーの解析に失敗: %w", err)
+		}
+		for _, member := range response {
+			members = append(members, member.Login)
+		}
+
+		path = ""
+		if m := nextLinkRE.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			path = m[1]
+		}
+	}
+	return members, nil
+}
+
+// FetchUsersPRs はユーザーごとにPRを並列で検索し、usersの順に結果をまとめる。
+// 各PRのUserには活動を判定したユーザーが設定される。
+// 検索に失敗したユーザーはErrorsに記録し、全員が失敗した場合かstrictの場合だけエラーを返す。
+func (c *PRClient) FetchUsersPRs(opts SearchOptions, users []string) ([]PullRequest, error) {
+	results := make([][]PullRequest, len(users))
+	errs := make([]error, len(users))
+	sem := make(chan struct{}, maxUserConcurrency)
+
+	var wg sync.WaitGroup
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, user string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			userOpts := opts
+			userOpts.User = user
+			prs, err := c.FetchTodaysPRs(userOpts)
+			if err != nil {
+				errs[i] = fmt.Errorf("%sのPRの取得に失敗: %w", user, err)
+				return
+			}
+			results[i] = prs
+		}(i, user)
+	}
+	wg.Wait()
+
+	// 存在しないユーザーなどで一部の検索が失敗しても、他のユーザーの結果は返す
+	var prs []PullRequest
+	var failures []PRError
+	for i := range users {
+		if errs[i] != nil {
+			failures = append(failures, newPRError("", 0, "", errs[i]))
+			continue
+		}
+		prs = append(prs, results[i]...)
+	}
+	if len(users) > 0 && len(failures) == len(users) {
+		return nil, errs[0]
+	}
+	if err := c.collectFailures(failures); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
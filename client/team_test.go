@@ -0,0 +1,140 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPRClient_FetchTeamMembers(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		if r.URL.Path != "/orgs/acme/teams/backend/members" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/acme/teams/backend/members?page=2>; rel="next"`, serverURL))
+			fmt.Fprint(w, `[{"login":"alice"},{"login":"bob"}]`)
+		case "2":
+			fmt.Fprint(w, `[{"login":"carol"}]`)
+		}
+	})
+	defer server.Close()
+
+	members, err := client.FetchTeamMembers("acme", "backend")
+	if err != nil {
+		t.Fatalf("FetchTeamMembers() error = %v", err)
+	}
+	if want := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(members, want) {
+		t.Errorf("FetchTeamMembers() = %v, want %v", members, want)
+	}
+}
+
+func TestPRClient_FetchUsersPRs(t *testing.T) {
+	server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+		if pullDetailPathRE.MatchString(r.URL.Path) {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		if r.URL.Path != "/search/issues" {
+			// コミットとイベントはなし
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		q := r.URL.Query().Get("q")
+		var items []searchItem
+		switch {
+		case strings.HasSuffix(q, "author:alice"):
+			items = searchItems(1, 1)
+		case strings.HasSuffix(q, "author:bob"):
+			items = searchItems(2, 2)
+		default:
+			t.Errorf("Unexpected query %s", q)
+		}
+		json.NewEncoder(w).Encode(searchResponse{Items: items, Total: len(items)})
+	})
+	defer server.Close()
+
+	prs, err := client.FetchUsersPRs(SearchOptions{Range: utcRange(t, "2024-01-01", "2024-01-31")}, []string{"bob", "alice"})
+	if err != nil {
+		t.Fatalf("FetchUsersPRs() error = %v", err)
+	}
+
+	counts := make(map[string]int)
+	var order []string
+	for _, pr := range prs {
+		if counts[pr.User] == 0 {
+			order = append(order, pr.User)
+		}
+		counts[pr.User]++
+	}
+	if want := []string{"bob", "alice"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("FetchUsersPRs() users = %v, want %v", order, want)
+	}
+	if counts["alice"] != 1 || counts["bob"] != 2 {
+		t.Errorf("FetchUsersPRs() counts = %v, want alice:1 bob:2", counts)
+	}
+}
+
+func TestPRClient_FetchUsersPRs_PartialFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		strict     bool
+		wantErr    bool
+		wantPRs    int
+		wantErrors int
+	}{
+		{
+			name:       "失敗したユーザーを記録して他のユーザーの結果を返す",
+			wantPRs:    1,
+			wantErrors: 1,
+		},
+		{
+			name:    "strictの場合はエラー",
+			strict:  true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := setupSearchServer(t, func(w http.ResponseWriter, r *http.Request, serverURL string) {
+				if pullDetailPathRE.MatchString(r.URL.Path) {
+					fmt.Fprint(w, `{}`)
+					return
+				}
+				if r.URL.Path != "/search/issues" {
+					fmt.Fprint(w, `[]`)
+					return
+				}
+				if strings.HasSuffix(r.URL.Query().Get("q"), "author:ghost") {
+					// 存在しないユーザーはValidation Failedになる
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message":"Validation Failed"}`)
+					return
+				}
+				json.NewEncoder(w).Encode(searchResponse{Items: searchItems(1, 1), Total: 1})
+			})
+			defer server.Close()
+			client.SetStrict(tt.strict)
+
+			prs, err := client.FetchUsersPRs(SearchOptions{Range: utcRange(t, "2024-01-01", "2024-01-31")}, []string{"alice", "ghost"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchUsersPRs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(prs) != tt.wantPRs {
+				t.Errorf("FetchUsersPRs() returned %d PRs, want %d", len(prs), tt.wantPRs)
+			}
+			failures := client.Errors()
+			if len(failures) != tt.wantErrors {
+				t.Fatalf("Errors() = %v, want %d errors", failures, tt.wantErrors)
+			}
+			if tt.wantErrors > 0 && !strings.Contains(failures[0].Error(), "ghost") {
+				t.Errorf("Errors()[0] = %v, want the user name", failures[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hiroyannnn/gh-pr-digest/config"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage default flag values and profiles",
+		Long: `設定ファイル（` + config.Path() + `）のフラグの既定値を表示・変更します。
+キーは org、repo、format、backend、timezone、roles、exclude_repos、labels、exclude_labels、bases、show、
+hostnames、hide_passive、all、exclude_author_bots、no_checks、no_review_status、size_thresholds.xs などです。profiles.<名前>.<キー> でプロファイルごとの値を、
+default_profile で --profile を省略したときのプロファイルを設定できます。`,
+		// 設定ファイルに誤りがあっても修正できるように既定値は適用しない
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a config key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := config.Get(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Update a config key (an empty value removes it)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return config.Set(args[0], args[1])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Print all config keys and values",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := config.List()
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				fmt.Printf("%s=%s\n", entry.Key, entry.Value)
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+// applyConfigDefaults は--profileで選んだ設定ファイルの既定値を、指定されなかったフラグに反映する。
+// サブコマンド固有のフラグ（weeklyの--formatなど）は値の意味が異なるため、ルートのフラグ
+// （runのようにルートと共有しているフラグを含む）だけに反映する。
+func applyConfigDefaults(cmd *cobra.Command) error {
+	defaults, err := loadDefaults(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range []struct {
+		key   string
+		flag  string
+		value string
+	}{
+		{"org", "org", defaults.Org},
+		{"repo", "repo", defaults.Repo},
+		{"format", "format", defaults.Format},
+		{"backend", "backend", defaults.Backend},
+		{"timezone", "tz", defaults.Timezone},
+		{"roles", "role", strings.Join(defaults.Roles, ",")},
+		{"exclude_repos", "exclude-repo", strings.Join(defaults.ExcludeRepos, ",")},
+		{"labels", "label", strings.Join(defaults.Labels, ",")},
+		{"exclude_labels", "exclude-label", strings.Join(defaults.ExcludeLabels, ",")},
+		{"bases", "base", strings.Join(defaults.Bases, ",")},
+		{"show", "show", strings.Join(defaults.Show, ",")},
+		{"hostnames", "hostname", strings.Join(defaults.Hostnames, ",")},
+		{"hide_passive", "hide-passive", boolValue(defaults.HidePassive)},
+		{"all", "all", boolValue(defaults.All)},
+		{"exclude_author_bots", "exclude-author-bots", boolValue(defaults.ExcludeAuthorBots)},
+		{"no_checks", "no-checks", boolValue(defaults.NoChecks)},
+		{"no_review_status", "no-review-status", boolValue(defaults.NoReviewStatus)},
+	} {
+		if d.value == "" {
+			continue
+		}
+		f := cmd.Flags().Lookup(d.flag)
+		if f == nil || f.Changed {
+			continue
+		}
+		if cmd.Root().Flags().Lookup(d.flag) != f {
+			continue
+		}
+		// Changedにはしない（--formatを明示したかどうかの判定に使うため）
+		if err := f.Value.Set(d.value); err != nil {
+			return fmt.Errorf("設定ファイルの%sを反映できません: %w", d.key, err)
+		}
+	}
+	return nil
+}
+
+// loadDefaults は設定ファイルから--profileで選んだ既定値を読み込む
+func loadDefaults(cmd *cobra.Command) (config.Defaults, error) {
+	profile, _ := cmd.Flags().GetString("profile")
+	cfg, err := config.Load()
+	if err != nil {
+		return config.Defaults{}, err
+	}
+	return cfg.Resolve(profile)
+}
+
+// boolValue は設定ファイルで指定しなかった真偽値を空文字列にする
+func boolValue(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
@@ -0,0 +1,369 @@
+// Package config はgh-pr-digestの設定ファイルを扱う
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	ghconfig "github.com/cli/go-gh/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Config は設定ファイルの内容
+type Config struct {
+	// プロファイルを指定しない場合の既定値
+	Defaults `yaml:",inline"`
+	// PRのサイズのラベルの閾値。省略した値はDefaultSizeThresholdsを使う
+	SizeThresholds *SizeThresholds `yaml:"size_thresholds,omitempty"`
+	// --profileで切り替える既定値の組み合わせ。指定した値だけが上書きされる
+	Profiles map[string]Defaults `yaml:"profiles,omitempty"`
+	// --profileを指定しない場合に使うプロファイル
+	DefaultProfile string `yaml:"default_profile,omitempty"`
+	// gh prd run <名前>で実行する検索の条件（--queryと同じ形式）
+	Queries map[string]string `yaml:"queries,omitempty"`
+}
+
+// Defaults はフラグの既定値。キーはフラグ名の-を_にしたもの（roles、exclude_repos、labels、exclude_labels、bases、hostnamesは複数形）
+type Defaults struct {
+	Org string `yaml:"org,omitempty"`
+	// カンマ区切りで複数指定でき、myorg/service-*のようなワイルドカードも使える
+	Repo    string `yaml:"repo,omitempty"`
+	Format  string `yaml:"format,omitempty"`
+	Backend string `yaml:"backend,omitempty"`
+	// 日の境界に使うタイムゾーン（例: Asia/Tokyo）。空の場合はローカルタイムゾーン
+	Timezone     string   `yaml:"timezone,omitempty"`
+	Roles        []string `yaml:"roles,omitempty"`
+	ExcludeRepos []string `yaml:"exclude_repos,omitempty"`
+	// ラベルとベースブランチでの絞り込み
+	Labels        []string `yaml:"labels,omitempty"`
+	ExcludeLabels []string `yaml:"exclude_labels,omitempty"`
+	Bases         []string `yaml:"bases,omitempty"`
+	Show          []string `yaml:"show,omitempty"`
+	// PRを取得するホスト。複数指定するとまとめて1つのダイジェストにする
+	Hostnames []string `yaml:"hostnames,omitempty"`
+	// 真偽値はプロファイルでfalseを指定して上書きできるように、指定しなかった場合をnilで表す
+	HidePassive *bool `yaml:"hide_passive,omitempty"`
+	// カレントディレクトリのリポジトリに絞り込まない
+	All               *bool `yaml:"all,omitempty"`
+	ExcludeAuthorBots *bool `yaml:"exclude_author_bots,omitempty"`
+	NoChecks          *bool `yaml:"no_checks,omitempty"`
+	NoReviewStatus    *bool `yaml:"no_review_status,omitempty"`
+}
+
+// merge はoの設定されている値でdを上書きした既定値を返す
+func (d Defaults) merge(o Defaults) Defaults {
+	if o.Org != "" {
+		d.Org = o.Org
+	}
+	if o.Repo != "" {
+		d.Repo = o.Repo
+	}
+	if o.Format != "" {
+		d.Format = o.Format
+	}
+	if o.Backend != "" {
+		d.Backend = o.Backend
+	}
+	if o.Timezone != "" {
+		d.Timezone = o.Timezone
+	}
+	if len(o.Roles) > 0 {
+		d.Roles = o.Roles
+	}
+	if len(o.ExcludeRepos) > 0 {
+		d.ExcludeRepos = o.ExcludeRepos
+	}
+	if len(o.Labels) > 0 {
+		d.Labels = o.Labels
+	}
+	if len(o.ExcludeLabels) > 0 {
+		d.ExcludeLabels = o.ExcludeLabels
+	}
+	if len(o.Bases) > 0 {
+		d.Bases = o.Bases
+	}
+	if len(o.Show) > 0 {
+		d.Show = o.Show
+	}
+	if len(o.Hostnames) > 0 {
+		d.Hostnames = o.Hostnames
+	}
+	if o.HidePassive != nil {
+		d.HidePassive = o.HidePassive
+	}
+	if o.All != nil {
+		d.All = o.All
+	}
+	if o.ExcludeAuthorBots != nil {
+		d.ExcludeAuthorBots = o.ExcludeAuthorBots
+	}
+	if o.NoChecks != nil {
+		d.NoChecks = o.NoChecks
+	}
+	if o.NoReviewStatus != nil {
+		d.NoReviewStatus = o.NoReviewStatus
+	}
+	return d
+}
+
+// Resolve はプロファイルの値で上書きした既定値を返す。
+// profileが空の場合はdefault_profileを使い、それも空の場合はプロファイルなしとする。
+func (c *Config) Resolve(profile string) (Defaults, error) {
+	if profile == "" {
+		profile = c.DefaultProfile
+	}
+	if profile == "" {
+		return c.Defaults, nil
+	}
+	p, ok := c.Profiles[profile]
+	if !ok {
+		return Defaults{}, fmt.Errorf("プロファイルが見つかりません: %s（設定されているプロファイル: %s）", profile, strings.Join(sortedKeys(c.Profiles), ", "))
+	}
+	return c.Defaults.merge(p), nil
+}
+
+// Query は名前で保存した検索の条件を返す
+func (c *Config) Query(name string) (string, error) {
+	query, ok := c.Queries[name]
+	if !ok {
+		return "", fmt.Errorf("保存したクエリが見つかりません: %s（保存されているクエリ: %s）", name, strings.Join(c.QueryNames(), ", "))
+	}
+	return query, nil
+}
+
+// QueryNames は保存した検索の条件の名前を名前の順に返す
+func (c *Config) QueryNames() []string {
+	return sortedKeys(c.Queries)
+}
+
+// sortedKeys はマップのキーを名前の順に返す
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SizeThresholds はPRのサイズのラベルを決める変更行数（追加と削除の合計）の閾値。
+// 行数がXS未満ならXS、S未満ならS、M未満ならM、L未満ならL、それ以上はXLとする。
+type SizeThresholds struct {
+	XS int `yaml:"xs,omitempty"`
+	S  int `yaml:"s,omitempty"`
+	M  int `yaml:"m,omitempty"`
+	L  int `yaml:"l,omitempty"`
+}
+
+// DefaultSizeThresholds はサイズのラベルの既定の閾値
+var DefaultSizeThresholds = SizeThresholds{XS: 10, S: 100, M: 500, L: 1000}
+
+// Sizes は省略した値を既定値で補ったサイズのラベルの閾値を返す
+func (c *Config) Sizes() SizeThresholds {
+	sizes := DefaultSizeThresholds
+	if s := c.SizeThresholds; s != nil {
+		if s.XS != 0 {
+			sizes.XS = s.XS
+		}
+		if s.S != 0 {
+			sizes.S = s.S
+		}
+		if s.M != 0 {
+			sizes.M = s.M
+		}
+		if s.L != 0 {
+			sizes.L = s.L
+		}
+	}
+	return sizes
+}
+
+// Dir は設定ファイルを置くディレクトリを返す
+func Dir() string {
+	return filepath.Join(ghconfig.ConfigDir(), "gh-pr-digest")
+}
+
+// TemplateDir は名前で選べる出力テンプレート（<名前>.tmpl）を置くディレクトリを返す
+func TemplateDir() string {
+	return filepath.Join(Dir(), "templates")
+}
+
+// CacheDir はAPIレスポンスのキャッシュを置くディレクトリを返す。
+// XDG_CACHE_HOMEが設定されていればそれを優先する。
+func CacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "gh-pr-digest")
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "gh-pr-digest-cache")
+	}
+	return filepath.Join(dir, "gh-pr-digest")
+}
+
+// Path は設定ファイルのパスを返す
+func Path() string {
+	return filepath.Join(Dir(), "config.yml")
+}
+
+// Load は設定ファイルを読み込む。ファイルがない場合は空の設定を返す。
+func Load() (*Config, error) {
+	return load(Path())
+}
+
+func load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイルの読み込みに失敗: %w", err)
+	}
+
+	cfg, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// parse は設定ファイルの内容を解析して検証する。不明なキーもエラーにする
+func parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("設定ファイルの解析に失敗: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validate は設定値を検証する。エラーには問題のあるキーを含める
+func (c *Config) validate() error {
+	if err := c.Defaults.validate(""); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(c.Profiles) {
+		if err := c.Profiles[name].validate("profiles." + name + "."); err != nil {
+			return err
+		}
+	}
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			return fmt.Errorf("default_profile: プロファイルが見つかりません: %s", c.DefaultProfile)
+		}
+	}
+	for _, name := range sortedKeys(c.Queries) {
+		if strings.TrimSpace(c.Queries[name]) == "" {
+			return fmt.Errorf("queries.%s: 検索の条件を指定してください", name)
+		}
+	}
+	if s := c.Sizes(); s.XS <= 0 || s.XS >= s.S || s.S >= s.M || s.M >= s.L {
+		return fmt.Errorf("size_thresholds: 0 < xs < s < m < l になるように指定してください（xs=%d, s=%d, m=%d, l=%d）",
+			s.XS, s.S, s.M, s.L)
+	}
+	return nil
+}
+
+// 値を選ぶキーで指定できる値
+var (
+	formats  = []string{"text", "json", "markdown", "slack"}
+	backends = []string{"rest", "graphql"}
+	roles    = []string{"author", "reviewer", "commenter", "assignee", "involves"}
+	details  = []string{"labels", "issues"}
+)
+
+func (d Defaults) validate(prefix string) error {
+	if d.Format != "" && !contains(formats, d.Format) {
+		return fmt.Errorf("%sformat: 不明な出力形式: %s（%sのいずれかを指定してください）", prefix, d.Format, strings.Join(formats, "/"))
+	}
+	if d.Backend != "" && !contains(backends, d.Backend) {
+		return fmt.Errorf("%sbackend: 不明なバックエンド: %s（%sのいずれかを指定してください）", prefix, d.Backend, strings.Join(backends, "/"))
+	}
+	if d.Timezone != "" {
+		if _, err := time.LoadLocation(d.Timezone); err != nil {
+			return fmt.Errorf("%stimezone: 不明なタイムゾーン: %s", prefix, d.Timezone)
+		}
+	}
+	for _, role := range d.Roles {
+		if !contains(roles, role) {
+			return fmt.Errorf("%sroles: 不明なロール: %s（%sのいずれかを指定してください）", prefix, role, strings.Join(roles, "/"))
+		}
+	}
+	for _, detail := range d.Show {
+		if !contains(details, detail) {
+			return fmt.Errorf("%sshow: 不明な詳細: %s（%sのいずれかを指定してください）", prefix, detail, strings.Join(details, "/"))
+		}
+	}
+	if d.Repo != "" {
+		for _, repo := range strings.Split(d.Repo, ",") {
+			if repo = strings.TrimSpace(repo); !isRepoName(repo) || !validPattern(repo) {
+				return fmt.Errorf("%srepo: owner/repo形式で指定してください: %s", prefix, repo)
+			}
+		}
+	}
+	for _, repo := range d.ExcludeRepos {
+		if !isRepoName(repo) || !validPattern(repo) {
+			return fmt.Errorf("%sexclude_repos: owner/repo形式で指定してください: %s", prefix, repo)
+		}
+	}
+	for _, base := range d.Bases {
+		if base == "" || !validPattern(base) {
+			return fmt.Errorf("%sbases: 不正なベースブランチ: %s", prefix, base)
+		}
+	}
+	for _, host := range d.Hostnames {
+		if !isHostname(host) {
+			return fmt.Errorf("%shostnames: ホスト名だけを指定してください（例: github.example.com）: %s", prefix, host)
+		}
+	}
+	return nil
+}
+
+// isHostname はスキームやパスを含まないホスト名かどうかを返す
+func isHostname(s string) bool {
+	return s != "" && !strings.ContainsAny(s, "/ ")
+}
+
+func isRepoName(s string) bool {
+	owner, name, ok := strings.Cut(s, "/")
+	return ok && owner != "" && name != "" && !strings.Contains(name, "/")
+}
+
+// validPattern はワイルドカードの書式が正しいかどうかを返す
+func validPattern(s string) bool {
+	_, err := path.Match(s, "")
+	return err == nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadLocation はタイムゾーン名を解決する。空の場合はローカルタイムゾーンを返す。
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("不明なタイムゾーン: %s", name)
+	}
+	return loc, nil
+}
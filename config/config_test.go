@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		content  string
+		timezone string
+		wantErr  string
+	}{
+		{name: "タイムゾーン指定", content: "timezone: Asia/Tokyo\n", timezone: "Asia/Tokyo"},
+		{name: "空のファイル", content: "", timezone: ""},
+		{name: "不明なタイムゾーン", content: "timezone: Mars/Olympus\n", wantErr: "timezone: 不明なタイムゾーン"},
+		{name: "サイズの閾値の一部を指定", content: "size_thresholds:\n  m: 300\n"},
+		{name: "サイズの閾値の順序が不正", content: "size_thresholds:\n  s: 600\n", wantErr: "size_thresholds:"},
+		{name: "不明なキー", content: "organization: myorg\n", wantErr: "field organization not found"},
+		{name: "プロファイルの不正な値", content: "profiles:\n  work:\n    roles: [author, owner]\n", wantErr: "profiles.work.roles: 不明なロール: owner"},
+		{name: "複数のリポジトリとワイルドカード", content: "repo: myorg/api, myorg/service-*\n"},
+		{name: "リポジトリのパターンが不正", content: "repo: myorg/api,myorg/[\n", wantErr: "repo: owner/repo形式で指定してください: myorg/["},
+		{name: "空の保存したクエリ", content: "queries:\n  go: \"\"\n", wantErr: "queries.go: 検索の条件を指定してください"},
+		{name: "リポジトリの形式", content: "exclude_repos: [legacy]\n", wantErr: "exclude_repos: owner/repo形式"},
+		{name: "ホスト名にURLを指定", content: "hostnames: [github.com, https://ghe.example.com]\n", wantErr: "hostnames: ホスト名だけを指定してください"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load() error = %v", err)
+			}
+			if cfg.Timezone != tt.timezone {
+				t.Errorf("Config.Timezone = %v, want %v", cfg.Timezone, tt.timezone)
+			}
+		})
+	}
+}
+
+func TestConfig_Sizes(t *testing.T) {
+	cfg := &Config{SizeThresholds: &SizeThresholds{M: 300}}
+	want := SizeThresholds{XS: 10, S: 100, M: 300, L: 1000}
+	if got := cfg.Sizes(); got != want {
+		t.Errorf("Config.Sizes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	yes, no := true, false
+	cfg := &Config{
+		Defaults: Defaults{Org: "myorg", Format: "markdown", Roles: []string{"author"}, HidePassive: &yes},
+		Profiles: map[string]Defaults{
+			"oss":  {Org: "cli", Roles: []string{"author", "reviewer"}, HidePassive: &no},
+			"work": {Repo: "myorg/api", All: &yes},
+		},
+		DefaultProfile: "work",
+	}
+
+	tests := []struct {
+		name     string
+		profile  string
+		expected Defaults
+		wantErr  bool
+	}{
+		{name: "default_profile", profile: "", expected: Defaults{Org: "myorg", Repo: "myorg/api", Format: "markdown", Roles: []string{"author"}, HidePassive: &yes, All: &yes}},
+		{name: "指定したプロファイルで上書き", profile: "oss", expected: Defaults{Org: "cli", Format: "markdown", Roles: []string{"author", "reviewer"}, HidePassive: &no}},
+		{name: "存在しないプロファイル", profile: "home", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.Resolve(tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Resolve() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfig_Query(t *testing.T) {
+	cfg := &Config{Queries: map[string]string{"go": "language:go", "unmerged": "is:unmerged"}}
+
+	if got, err := cfg.Query("go"); err != nil || got != "language:go" {
+		t.Errorf("Query(go) = %q, %v, want language:go", got, err)
+	}
+	_, err := cfg.Query("rust")
+	if err == nil || !strings.Contains(err.Error(), "保存されているクエリ: go, unmerged") {
+		t.Errorf("Query(rust) error = %v, want the saved query names", err)
+	}
+}
+
+func TestLoad_NotExist(t *testing.T) {
+	cfg, err := load(filepath.Join(t.TempDir(), "config.yml"))
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if cfg.Timezone != "" {
+		t.Errorf("Config.Timezone = %v, want empty", cfg.Timezone)
+	}
+}
@@ -0,0 +1,291 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry はconfig listで表示する設定値
+type Entry struct {
+	Key   string
+	Value string
+}
+
+// keyKind は設定値の種類
+type keyKind int
+
+const (
+	kindString keyKind = iota
+	kindList
+	kindBool
+	kindInt
+)
+
+// defaultsKeys はDefaultsのキーと値の種類
+var defaultsKeys = map[string]keyKind{
+	"org":                 kindString,
+	"repo":                kindString,
+	"format":              kindString,
+	"backend":             kindString,
+	"timezone":            kindString,
+	"roles":               kindList,
+	"exclude_repos":       kindList,
+	"show":                kindList,
+	"labels":              kindList,
+	"exclude_labels":      kindList,
+	"bases":               kindList,
+	"hostnames":           kindList,
+	"hide_passive":        kindBool,
+	"all":                 kindBool,
+	"exclude_author_bots": kindBool,
+	"no_checks":           kindBool,
+	"no_review_status":    kindBool,
+}
+
+// lookupKey は"profiles.work.org"のようなドット区切りのキーの値の種類を返す
+func lookupKey(key string) (keyKind, error) {
+	parts := strings.Split(key, ".")
+	switch {
+	case len(parts) == 1 && key == "default_profile":
+		return kindString, nil
+	case len(parts) == 1:
+		if kind, ok := defaultsKeys[key]; ok {
+			return kind, nil
+		}
+	case len(parts) == 2 && parts[0] == "queries" && parts[1] != "":
+		return kindString, nil
+	case len(parts) == 2 && parts[0] == "size_thresholds":
+		switch parts[1] {
+		case "xs", "s", "m", "l":
+			return kindInt, nil
+		}
+	case len(parts) == 3 && parts[0] == "profiles" && parts[1] != "":
+		if kind, ok := defaultsKeys[parts[2]]; ok {
+			return kind, nil
+		}
+	}
+	return 0, fmt.Errorf("不明なキー: %s", key)
+}
+
+// Get は設定値を返す。設定されていない場合は空文字列を返す
+func Get(key string) (string, error) {
+	return get(Path(), key)
+}
+
+// Set は設定値を変更して設定ファイルに書き込む。
+// 値が空の場合は設定を削除する。rolesなどの複数の値はカンマ区切りで指定する。
+func Set(key, value string) error {
+	return set(Path(), key, value)
+}
+
+// List は設定されている値をファイルに書かれている順に返す
+func List() ([]Entry, error) {
+	return list(Path())
+}
+
+func get(path, key string) (string, error) {
+	if _, err := lookupKey(key); err != nil {
+		return "", err
+	}
+	root, _, err := readDocument(path)
+	if err != nil {
+		return "", err
+	}
+
+	node := root
+	for _, part := range strings.Split(key, ".") {
+		if node = mappingValue(node, part); node == nil {
+			return "", nil
+		}
+	}
+	return nodeValue(node), nil
+}
+
+func set(path, key, value string) error {
+	kind, err := lookupKey(key)
+	if err != nil {
+		return err
+	}
+	root, doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(key, ".")
+	parent := root
+	for _, part := range parts[:len(parts)-1] {
+		child := mappingValue(parent, part)
+		if child == nil {
+			if value == "" {
+				return nil
+			}
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, child)
+		}
+		parent = child
+	}
+
+	name := parts[len(parts)-1]
+	if value == "" {
+		removeMappingValue(parent, name)
+	} else {
+		node, err := valueNode(kind, key, value)
+		if err != nil {
+			return err
+		}
+		if existing := mappingValue(parent, name); existing != nil {
+			*existing = *node
+		} else {
+			parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, node)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("設定ファイルの作成に失敗: %w", err)
+	}
+	// 書き込む前に変更後の内容を検証する
+	if _, err := parse(buf.Bytes()); err != nil {
+		return err
+	}
+	return writeFile(path, buf.Bytes())
+}
+
+func list(path string) ([]Entry, error) {
+	root, _, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	return flatten(root, ""), nil
+}
+
+// readDocument は設定ファイルをコメントを保ったまま読み込み、最上位のマッピングとドキュメントを返す
+func readDocument(path string) (*yaml.Node, *yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("設定ファイルの読み込みに失敗: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("%s: 設定ファイルの解析に失敗: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		// 空のファイル
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("%s: 設定ファイルの最上位はマッピングにしてください", path)
+	}
+	return doc.Content[0], &doc, nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func removeMappingValue(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// valueNode は文字列で指定された値をキーの種類に合わせたノードにする
+func valueNode(kind keyKind, key, value string) (*yaml.Node, error) {
+	switch kind {
+	case kindList:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+			}
+		}
+		return node, nil
+	case kindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: true/falseのいずれかを指定してください: %s", key, value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(b)}, nil
+	case kindInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: 整数を指定してください: %s", key, value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(n)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+}
+
+// nodeValue はノードの値を文字列にする。リストはカンマ区切りにする
+func nodeValue(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			values = append(values, item.Value)
+		}
+		return strings.Join(values, ",")
+	default:
+		return node.Value
+	}
+}
+
+// flatten はマッピングを"profiles.work.org"のようなドット区切りのキーの一覧にする
+func flatten(node *yaml.Node, prefix string) []Entry {
+	var entries []Entry
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := prefix+node.Content[i].Value, node.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			entries = append(entries, flatten(value, key+".")...)
+			continue
+		}
+		entries = append(entries, Entry{Key: key, Value: nodeValue(value)})
+	}
+	return entries
+}
+
+// writeFile は書き込み途中の内容を読まれないように一時ファイルを経由して書き込む
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("設定ディレクトリの作成に失敗: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.yml")
+	if err != nil {
+		return fmt.Errorf("設定ファイルの書き込みに失敗: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("設定ファイルの書き込みに失敗: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("設定ファイルの書き込みに失敗: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("設定ファイルの書き込みに失敗: %w", err)
+	}
+	return nil
+}
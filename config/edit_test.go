@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetGetList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gh-pr-digest", "config.yml")
+	initial := "# タイムゾーン\ntimezone: Asia/Tokyo\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(initial), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kv := range [][2]string{
+		{"org", "myorg"},
+		{"roles", "author, reviewer"},
+		{"profiles.oss.org", "cli"},
+		{"size_thresholds.m", "300"},
+		{"profiles.oss.hide_passive", "true"},
+		{"queries.unmerged", "is:unmerged language:go"},
+	} {
+		if err := set(path, kv[0], kv[1]); err != nil {
+			t.Fatalf("set(%s, %s) error = %v", kv[0], kv[1], err)
+		}
+	}
+
+	if got, err := get(path, "roles"); err != nil || got != "author,reviewer" {
+		t.Errorf("get(roles) = %q, %v, want author,reviewer", got, err)
+	}
+	if got, err := get(path, "repo"); err != nil || got != "" {
+		t.Errorf("get(repo) = %q, %v, want empty", got, err)
+	}
+
+	entries, err := list(path)
+	if err != nil {
+		t.Fatalf("list() error = %v", err)
+	}
+	want := []Entry{
+		{Key: "timezone", Value: "Asia/Tokyo"},
+		{Key: "org", Value: "myorg"},
+		{Key: "roles", Value: "author,reviewer"},
+		{Key: "profiles.oss.org", Value: "cli"},
+		{Key: "profiles.oss.hide_passive", Value: "true"},
+		{Key: "size_thresholds.m", Value: "300"},
+		{Key: "queries.unmerged", Value: "is:unmerged language:go"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("list() = %v, want %v", entries, want)
+	}
+
+	// コメントは保たれ、書き込んだ内容はそのまま読み込める
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# タイムゾーン") {
+		t.Errorf("comment was lost:\n%s", data)
+	}
+	cfg, err := load(path)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if d, err := cfg.Resolve("oss"); err != nil || d.Org != "cli" || d.HidePassive == nil || !*d.HidePassive || len(d.Roles) != 2 {
+		t.Errorf("Resolve(oss) = %+v, %v", d, err)
+	}
+
+	// 空の値で削除する
+	if err := set(path, "org", ""); err != nil {
+		t.Fatalf("set(org, \"\") error = %v", err)
+	}
+	if got, _ := get(path, "org"); got != "" {
+		t.Errorf("get(org) = %q after removal, want empty", got)
+	}
+}
+
+func TestSet_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{name: "不明なキー", key: "color", value: "always", wantErr: "不明なキー: color"},
+		{name: "不明なプロファイルのキー", key: "profiles.work.since", value: "7d", wantErr: "不明なキー"},
+		{name: "不明な出力形式", key: "profiles.work.format", value: "html", wantErr: "profiles.work.format: 不明な出力形式"},
+		{name: "真偽値でない", key: "no_checks", value: "maybe", wantErr: "no_checks: true/false"},
+		{name: "整数でない", key: "size_thresholds.xs", value: "ten", wantErr: "size_thresholds.xs: 整数"},
+		{name: "存在しないプロファイル", key: "default_profile", value: "work", wantErr: "default_profile: プロファイルが見つかりません"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := set(path, tt.key, tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("set() error = %v, want %q", err, tt.wantErr)
+			}
+			// 検証に失敗した場合は書き込まない
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("config file was written: %v", err)
+			}
+		})
+	}
+}
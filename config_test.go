@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyConfigDefaults(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	content := `org: myorg
+format: markdown
+roles: [author, reviewer]
+hide_passive: true
+profiles:
+  oss:
+    org: cli
+    hide_passive: false
+`
+	if err := os.MkdirAll(filepath.Join(dir, "gh-pr-digest"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gh-pr-digest", "config.yml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		root := &cobra.Command{Use: "pr-digest"}
+		root.PersistentFlags().StringP("org", "o", "", "")
+		root.PersistentFlags().String("profile", "", "")
+		root.Flags().String("format", "text", "")
+		root.Flags().StringSlice("role", []string{"author"}, "")
+		root.Flags().Bool("hide-passive", false, "")
+		if err := root.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		org         string
+		format      string
+		roles       int
+		hidePassive bool
+	}{
+		{name: "既定値", org: "myorg", format: "markdown", roles: 2, hidePassive: true},
+		{name: "フラグを優先", args: []string{"--format", "json", "-o", "other"}, org: "other", format: "json", roles: 2, hidePassive: true},
+		{name: "プロファイルのfalseで上書き", args: []string{"--profile", "oss"}, org: "cli", format: "markdown", roles: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newCmd(tt.args...)
+			if err := applyConfigDefaults(cmd); err != nil {
+				t.Fatalf("applyConfigDefaults() error = %v", err)
+			}
+			org, _ := cmd.Flags().GetString("org")
+			format, _ := cmd.Flags().GetString("format")
+			roles, _ := cmd.Flags().GetStringSlice("role")
+			hidePassive, _ := cmd.Flags().GetBool("hide-passive")
+			if org != tt.org || format != tt.format || len(roles) != tt.roles || hidePassive != tt.hidePassive {
+				t.Errorf("flags = org:%s format:%s roles:%v hide-passive:%v", org, format, roles, hidePassive)
+			}
+			// 設定ファイルの値は明示的な指定として扱わない
+			if len(tt.args) == 0 && cmd.Flags().Changed("format") {
+				t.Error("format is marked as changed")
+			}
+		})
+	}
+}
+
+func TestApplyConfigDefaults_Subcommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "gh-pr-digest"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gh-pr-digest", "config.yml"), []byte("format: slack\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &cobra.Command{Use: "pr-digest"}
+	root.PersistentFlags().String("profile", "", "")
+	root.Flags().String("format", "text", "")
+	// runはルートのフラグを共有する
+	run := &cobra.Command{Use: "run"}
+	run.Flags().AddFlagSet(root.LocalNonPersistentFlags())
+	// weeklyの--formatは値の意味が異なる
+	weekly := &cobra.Command{Use: "weekly"}
+	weekly.Flags().String("format", "text", "")
+	root.AddCommand(run, weekly)
+
+	for cmd, want := range map[*cobra.Command]string{run: "slack", weekly: "text"} {
+		if err := applyConfigDefaults(cmd); err != nil {
+			t.Fatalf("applyConfigDefaults(%s) error = %v", cmd.Name(), err)
+		}
+		if got, _ := cmd.Flags().GetString("format"); got != want {
+			t.Errorf("%s --format = %v, want %v", cmd.Name(), got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/spf13/cobra"
+)
+
+// parseFilters は絞り込みのフラグを検索の条件にする。
+// 指定しなかったフラグには設定ファイルの値（exclude_reposやlabelsなど）が反映されている
+func parseFilters(cmd *cobra.Command) (client.Filters, error) {
+	excludeRepos, _ := cmd.Flags().GetStringSlice("exclude-repo")
+	labels, _ := cmd.Flags().GetStringSlice("label")
+	excludeLabels, _ := cmd.Flags().GetStringSlice("exclude-label")
+	bases, _ := cmd.Flags().GetStringSlice("base")
+	excludeBots, _ := cmd.Flags().GetBool("exclude-author-bots")
+
+	filters := client.Filters{
+		ExcludeRepos:  trimValues(excludeRepos),
+		Labels:        trimValues(labels),
+		ExcludeLabels: trimValues(excludeLabels),
+		Bases:         trimValues(bases),
+		ExcludeBots:   excludeBots,
+	}
+	for _, repo := range filters.ExcludeRepos {
+		if err := client.ValidateRepo(repo); err != nil {
+			return client.Filters{}, fmt.Errorf("--exclude-repo: %w", err)
+		}
+	}
+	for _, base := range filters.Bases {
+		if err := client.ValidateBase(base); err != nil {
+			return client.Filters{}, err
+		}
+	}
+	return filters, nil
+}
+
+// trimValues は空白を取り除き、空の値を除く
+func trimValues(values []string) []string {
+	var trimmed []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/spf13/cobra"
+)
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    client.Filters
+		wantErr bool
+	}{
+		{
+			name: "指定なし",
+			want: client.Filters{},
+		},
+		{
+			name: "繰り返しとカンマ区切り",
+			args: []string{"--exclude-repo", "owner/sandbox, owner/tmp-*", "--label", "bug", "--label", "urgent", "--exclude-label", "dependencies", "--base", "main", "--exclude-author-bots"},
+			want: client.Filters{
+				ExcludeRepos:  []string{"owner/sandbox", "owner/tmp-*"},
+				Labels:        []string{"bug", "urgent"},
+				ExcludeLabels: []string{"dependencies"},
+				Bases:         []string{"main"},
+				ExcludeBots:   true,
+			},
+		},
+		{
+			name:    "除外するリポジトリの形式",
+			args:    []string{"--exclude-repo", "sandbox"},
+			wantErr: true,
+		},
+		{
+			name:    "ベースブランチのパターンが不正",
+			args:    []string{"--base", "release/["},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "pr-digest"}
+			cmd.Flags().StringSlice("exclude-repo", nil, "")
+			cmd.Flags().StringSlice("label", nil, "")
+			cmd.Flags().StringSlice("exclude-label", nil, "")
+			cmd.Flags().StringSlice("base", nil, "")
+			cmd.Flags().Bool("exclude-author-bots", false, "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := parseFilters(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFilters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/spf13/cobra"
+)
+
+// hostClients はホストごとのクライアント。--hostnameで複数のホストを指定した場合は、
+// それぞれのホストのPRをまとめて1つのダイジェストにする
+type hostClients []*client.PRClient
+
+// newClients は--hostnameのホストごとにクライアントを作成する。
+// 省略時はカレントディレクトリから推測したリポジトリのホスト（repoHost）か、既定のホスト（GH_HOSTなど）を使う
+func newClients(cmd *cobra.Command, repoHost string) (hostClients, error) {
+	values, _ := cmd.Flags().GetStringSlice("hostname")
+	hosts, err := parseHostnames(values)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		hosts = []string{repoHost}
+	}
+
+	var clients hostClients
+	for _, host := range hosts {
+		c, err := newClient(cmd, host)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+// parseHostnames は空白を取り除き、大文字小文字を区別せずに重複を除いたホスト名を返す
+func parseHostnames(values []string) ([]string, error) {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		host := strings.ToLower(strings.TrimSpace(value))
+		if host == "" || seen[host] {
+			continue
+		}
+		if strings.Contains(host, "/") {
+			return nil, fmt.Errorf("不正なホスト名: %s（github.example.comのようにホスト名だけを指定してください）", value)
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// fetch はホストごとに順にPRを取得し、ホストの順にまとめる。
+// 複数のホストを指定した場合は、一部のホストで失敗しても他のホストのPRを返し、
+// 失敗したホストはエラーに記録する（--strictの場合とすべてのホストで失敗した場合はエラー）
+func (cs hostClients) fetch(fn func(c *client.PRClient) ([]client.PullRequest, error)) ([]client.PullRequest, error) {
+	if len(cs) == 1 {
+		return fn(cs[0])
+	}
+
+	var prs []client.PullRequest
+	var firstErr error
+	failed := 0
+	for _, c := range cs {
+		hostPRs, err := fn(c)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", c.Host(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			if err := c.RecordFailure(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		prs = append(prs, hostPRs...)
+	}
+	if failed == len(cs) {
+		return nil, firstErr
+	}
+	return prs, nil
+}
+
+// Errors はすべてのホストで取得に失敗したPRを返す
+func (cs hostClients) Errors() []client.PRError {
+	var failures []client.PRError
+	for _, c := range cs {
+		failures = append(failures, c.Errors()...)
+	}
+	return failures
+}
+
+// Warnings はすべてのホストの警告を返す。複数のホストを指定した場合はホスト名を付ける
+func (cs hostClients) Warnings() []string {
+	var warnings []string
+	for _, c := range cs {
+		for _, w := range c.Warnings() {
+			if len(cs) > 1 {
+				w = c.Host() + ": " + w
+			}
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// RateLimitSummary はホストごとのレート制限の状況をまとめて返す
+func (cs hostClients) RateLimitSummary() string {
+	if len(cs) == 1 {
+		return cs[0].RateLimitSummary()
+	}
+	var b strings.Builder
+	for _, c := range cs {
+		fmt.Fprintf(&b, "[%s]\n%s", c.Host(), c.RateLimitSummary())
+	}
+	return b.String()
+}
+
+// repositoryName はPRのリポジトリの表示名を返す。
+// github.com以外のホストのPRはホスト名を付けて、別のホストの同じ名前のリポジトリと区別する
+func repositoryName(pr client.PullRequest) string {
+	if pr.Host == "" || pr.Host == "github.com" {
+		return pr.Repository.FullName
+	}
+	return pr.Host + "/" + pr.Repository.FullName
+}
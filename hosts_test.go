@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestParseHostnames(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "指定なし",
+			values: nil,
+			want:   nil,
+		},
+		{
+			name:   "空白と重複を除く",
+			values: []string{" github.com", "GHE.example.com", "ghe.example.com", ""},
+			want:   []string{"github.com", "ghe.example.com"},
+		},
+		{
+			name:    "URLを指定",
+			values:  []string{"https://ghe.example.com"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHostnames(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHostnames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHostnames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryName(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{
+			name: "github.com",
+			host: "github.com",
+			want: "owner/repo",
+		},
+		{
+			name: "ホストなし",
+			host: "",
+			want: "owner/repo",
+		},
+		{
+			name: "GitHub Enterprise Server",
+			host: "ghe.example.com",
+			want: "ghe.example.com/owner/repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := client.PullRequest{Host: tt.host}
+			pr.Repository.FullName = "owner/repo"
+			if got := repositoryName(pr); got != tt.want {
+				t.Errorf("repositoryName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostClients_fetch(t *testing.T) {
+	// クライアントの作成に必要なトークン。APIにはアクセスしない
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GH_TOKEN", "token")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "token")
+
+	tests := []struct {
+		name       string
+		hosts      []string
+		failHosts  []string
+		strict     bool
+		wantPRs    []string
+		wantErrors int
+		wantErr    bool
+	}{
+		{
+			name:    "すべてのホストで成功",
+			hosts:   []string{"github.com", "ghe.example.com"},
+			wantPRs: []string{"github.com", "ghe.example.com"},
+		},
+		{
+			name:       "一部のホストで失敗した場合は他のホストのPRを返す",
+			hosts:      []string{"github.com", "ghe.example.com"},
+			failHosts:  []string{"github.com"},
+			wantPRs:    []string{"ghe.example.com"},
+			wantErrors: 1,
+		},
+		{
+			name:      "strictの場合はエラー",
+			hosts:     []string{"github.com", "ghe.example.com"},
+			failHosts: []string{"ghe.example.com"},
+			strict:    true,
+			wantErr:   true,
+		},
+		{
+			name:      "すべてのホストで失敗した場合はエラー",
+			hosts:     []string{"github.com", "ghe.example.com"},
+			failHosts: []string{"github.com", "ghe.example.com"},
+			wantErr:   true,
+		},
+		{
+			name:      "ホストが1つの場合はエラー",
+			hosts:     []string{"github.com"},
+			failHosts: []string{"github.com"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clients hostClients
+			for _, host := range tt.hosts {
+				c, err := client.NewPRClient(client.ClientOptions{Host: host})
+				if err != nil {
+					t.Fatalf("NewPRClient() error = %v", err)
+				}
+				c.SetStrict(tt.strict)
+				clients = append(clients, c)
+			}
+
+			prs, err := clients.fetch(func(c *client.PRClient) ([]client.PullRequest, error) {
+				for _, host := range tt.failHosts {
+					if c.Host() == host {
+						return nil, errors.New("認証に失敗")
+					}
+				}
+				return []client.PullRequest{{Host: c.Host()}}, nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, pr := range prs {
+				got = append(got, pr.Host)
+			}
+			if !reflect.DeepEqual(got, tt.wantPRs) {
+				t.Errorf("fetch() hosts = %v, want %v", got, tt.wantPRs)
+			}
+			if failures := clients.Errors(); len(failures) != tt.wantErrors {
+				t.Errorf("Errors() = %+v, want %d failures", failures, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/cli/go-gh/pkg/jq"
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/spf13/cobra"
+)
+
+// digestJSON はJSON出力の形式
+type digestJSON struct {
+	PullRequests []client.PullRequest `json:"pull_requests"`
+	// 取得に失敗したPR
+	Errors []client.PRError `json:"errors"`
+}
+
+func newDigestJSON(prs []client.PullRequest, failures []client.PRError) digestJSON {
+	out := digestJSON{
+		PullRequests: prs,
+		Errors:       failures,
+	}
+	// 件数が0でも配列として出力する
+	if out.PullRequests == nil {
+		out.PullRequests = []client.PullRequest{}
+	}
+	if out.Errors == nil {
+		out.Errors = []client.PRError{}
+	}
+	return out
+}
+
+// jsonOptions はJSON出力の加工方法
+type jsonOptions struct {
+	// PRのフィールドのうち出力するもの（空の場合はすべて）
+	fields []string
+	// 出力を加工するjqの式
+	jq string
+}
+
+func (o jsonOptions) enabled() bool {
+	return len(o.fields) > 0 || o.jq != ""
+}
+
+func parseJSONOptions(cmd *cobra.Command) (jsonOptions, error) {
+	fields, _ := cmd.Flags().GetStringSlice("json")
+	expr, _ := cmd.Flags().GetString("jq")
+
+	available := pullRequestFields()
+	for _, field := range fields {
+		if !contains(available, field) {
+			return jsonOptions{}, fmt.Errorf("不明なJSONフィールド: %s\n使用できるフィールド:\n  %s",
+				field, strings.Join(available, "\n  "))
+		}
+	}
+	return jsonOptions{fields: fields, jq: expr}, nil
+}
+
+// pullRequestFields はclient.PullRequestのJSONのフィールド名を返す
+func pullRequestFields() []string {
+	t := reflect.TypeOf(client.PullRequest{})
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func outputJSON(w io.Writer, prs []client.PullRequest, failures []client.PRError, opts jsonOptions) error {
+	var data interface{} = newDigestJSON(prs, failures)
+	if len(opts.fields) > 0 {
+		selected, err := selectFields(prs, opts.fields)
+		if err != nil {
+			return err
+		}
+		data = struct {
+			PullRequests []map[string]interface{} `json:"pull_requests"`
+			Errors       []client.PRError         `json:"errors"`
+		}{selected, newDigestJSON(prs, failures).Errors}
+	}
+
+	if opts.jq != "" {
+		input, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("JSONの作成に失敗: %w", err)
+		}
+		if err := jq.Evaluate(bytes.NewReader(input), w, opts.jq); err != nil {
+			return fmt.Errorf("jqの評価に失敗: %w", err)
+		}
+		return nil
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// selectFields はPRごとに指定したフィールドだけを取り出す。値がないフィールドはnullにする
+func selectFields(prs []client.PullRequest, fields []string) ([]map[string]interface{}, error) {
+	selected := make([]map[string]interface{}, 0, len(prs))
+	for _, pr := range prs {
+		data, err := json.Marshal(pr)
+		if err != nil {
+			return nil, fmt.Errorf("JSONの作成に失敗: %w", err)
+		}
+		var all map[string]interface{}
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, fmt.Errorf("JSONの作成に失敗: %w", err)
+		}
+
+		m := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			m[field] = all[field]
+		}
+		selected = append(selected, m)
+	}
+	return selected, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestOutputJSON(t *testing.T) {
+	pr := client.PullRequest{Title: "新機能の追加", HTMLURL: "https://github.com/owner/repo/pull/1", State: "open", Number: 1}
+	pr.Repository.FullName = "owner/repo"
+	prs := []client.PullRequest{pr}
+	failures := []client.PRError{{Repository: "owner/sso", Number: 2, Message: "HTTP 403"}}
+
+	tests := []struct {
+		name     string
+		opts     jsonOptions
+		expected string
+	}{
+		{
+			name:     "フィールドを選択",
+			opts:     jsonOptions{fields: []string{"number", "title", "review_state"}},
+			expected: `{"pull_requests":[{"number":1,"review_state":null,"title":"新機能の追加"}],"errors":[{"repository":"owner/sso","number":2,"message":"HTTP 403"}]}`,
+		},
+		{
+			name:     "jqで加工",
+			opts:     jsonOptions{jq: `.pull_requests[] | "\(.repository.full_name)#\(.number)"`},
+			expected: "owner/repo#1\n",
+		},
+		{
+			name:     "フィールドを選択してjqで加工",
+			opts:     jsonOptions{fields: []string{"html_url"}, jq: `.pull_requests | map(keys)`},
+			expected: `[["html_url"]]` + "\n",
+		},
+		{
+			name:     "エラーの件数",
+			opts:     jsonOptions{jq: `.errors | length`},
+			expected: "1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := outputJSON(&buf, prs, failures, tt.opts); err != nil {
+				t.Fatalf("outputJSON() error = %v", err)
+			}
+			got := buf.String()
+			if tt.opts.jq == "" {
+				got = compactJSON(t, got)
+			}
+			if got != tt.expected {
+				t.Errorf("outputJSON() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func compactJSON(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestPullRequestFields(t *testing.T) {
+	fields := pullRequestFields()
+	for _, want := range []string{"title", "html_url", "repository", "contribution", "review_decision"} {
+		if !contains(fields, want) {
+			t.Errorf("pullRequestFields() does not contain %s: %v", want, fields)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/hiroyannnn/gh-pr-digest/config"
 	"github.com/spf13/cobra"
 )
 
@@ -15,17 +17,56 @@ func main() {
 		Use:     "pr-digest",
 		Short:   "Show today's pull requests",
 		Aliases: []string{"prd"},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return applyConfigDefaults(cmd)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRoot(cmd)
 		},
 	}
 
-	rootCmd.Flags().StringP("org", "o", "", "指定した組織のPRを表示")
-	rootCmd.Flags().StringP("repo", "r", "", "指定したリポジトリのPRを表示")
-	rootCmd.Flags().String("format", "text", "出力形式（text/json）")
-	rootCmd.Flags().String("since", "", "指定した日付以降のPRを表示（YYYY-MM-DD形式）")
-	rootCmd.Flags().String("until", "", "指定した日付までのPRを表示（YYYY-MM-DD形式）")
-	rootCmd.Flags().Bool("debug", false, "デバッグ情報を表示")
+	rootCmd.PersistentFlags().StringP("org", "o", "", "指定した組織のPRを表示")
+	rootCmd.PersistentFlags().StringSliceP("repo", "r", nil, "指定したリポジトリのPRを表示（カンマ区切りで複数指定可、myorg/service-*のようなワイルドカードも使える。省略時はカレントディレクトリのリポジトリ）")
+	rootCmd.PersistentFlags().Bool("all", false, "カレントディレクトリのリポジトリに絞り込まずにすべてのリポジトリのPRを表示")
+	rootCmd.MarkFlagsMutuallyExclusive("all", "repo")
+	rootCmd.Flags().String("format", "text", "出力形式（text/json/markdown/slack）")
+	rootCmd.Flags().String("post", "", "SlackのIncoming WebhookのURLにBlock Kit形式で投稿")
+	rootCmd.Flags().Bool("dry-run", false, "--postで投稿せずに送信する内容を表示")
+	rootCmd.Flags().StringSlice("json", nil, "JSON出力に含めるPRのフィールド（カンマ区切り、例: title,html_url,state）")
+	rootCmd.Flags().String("jq", "", "JSON出力をjqの式で加工（例: '.pull_requests[].html_url'）")
+	rootCmd.Flags().String("template", "", "Goのテンプレートで出力（timeago、truncate、color、hyperlink、groupBy、pluralize、dateなどの関数が使えます）")
+	rootCmd.Flags().String("template-file", "", "ファイルから読み込んだGoのテンプレートで出力")
+	rootCmd.Flags().String("template-name", "", "設定ディレクトリのtemplates/<名前>.tmplで出力")
+	rootCmd.MarkFlagsMutuallyExclusive("format", "template", "template-file", "template-name")
+	rootCmd.Flags().String("since", "", "指定した日付以降のPRを表示（YYYY-MM-DD、yesterday、this-week、last-week、this-month、last-business-day、7d、2026-W41など）")
+	rootCmd.Flags().String("until", "", "指定した日付までのPRを表示（--sinceと同じ形式、期間の指定はその終わりまで）")
+	rootCmd.Flags().StringSlice("role", []string{"author"}, "自分との関係（author/reviewer/commenter/assignee/involves、カンマ区切りで複数指定可）")
+	rootCmd.Flags().Bool("hide-passive", false, "期間内に自分の活動がない（他の人のコメントなどで更新されただけの）PRを表示しない")
+	rootCmd.Flags().StringSlice("show", nil, "テキスト出力に表示する詳細（labels: ラベル・マイルストーン・担当者、issues: クローズするIssue、カンマ区切り）")
+	rootCmd.Flags().Bool("no-checks", false, "オープンなPRのCIの状態を取得しない（API呼び出しを減らせる）")
+	rootCmd.Flags().Bool("no-review-status", false, "オープンなPRのレビュー状況を取得しない（API呼び出しを減らせる）")
+	rootCmd.Flags().StringSlice("user", nil, "指定したユーザーのPRを表示（カンマ区切りで複数指定可、人ごとにまとめて表示）")
+	rootCmd.Flags().String("team", "", "指定したチームのメンバーのPRを表示（org/team-slug形式）")
+	rootCmd.Flags().String("query", "", "検索の条件をそのまま追加（例: 'is:unmerged language:go'、期間や自分との関係は各フラグで指定）")
+	rootCmd.PersistentFlags().StringSlice("exclude-repo", nil, "検索から除外するリポジトリ（カンマ区切りで複数指定可、myorg/tmp-*のようなワイルドカードも使える）")
+	rootCmd.PersistentFlags().StringSlice("label", nil, "指定したラベルがすべて付いたPRだけを表示（カンマ区切りで複数指定可）")
+	rootCmd.PersistentFlags().StringSlice("exclude-label", nil, "指定したラベルのいずれかが付いたPRを表示しない（カンマ区切りで複数指定可）")
+	rootCmd.PersistentFlags().StringSlice("base", nil, "ベースブランチがいずれかに一致するPRだけを表示（カンマ区切りで複数指定可、release/*のようなワイルドカードも使える）")
+	rootCmd.PersistentFlags().Bool("exclude-author-bots", false, "ボット（dependabotなど）が作成したPRを表示しない")
+	rootCmd.PersistentFlags().StringSlice("hostname", nil, "PRを取得するホスト（GitHub Enterprise Serverのホスト名など、カンマ区切りで複数指定するとまとめて表示）")
+	rootCmd.PersistentFlags().String("tz", "", "日の境界に使うタイムゾーン（例: Asia/Tokyo、省略時はローカルタイムゾーン）")
+	rootCmd.PersistentFlags().String("backend", "rest", "データ取得に使用するAPI（rest/graphql）")
+	rootCmd.PersistentFlags().Int("max-concurrency", client.DefaultMaxConcurrency, "PRの詳細を並列で取得するときの同時実行数の上限（レート制限を受けると自動で減らします）")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "APIレスポンスのキャッシュを使わない")
+	rootCmd.PersistentFlags().Bool("strict", false, "PRの取得に1件でも失敗したらエラーで終了する（既定では取得できたPRだけを表示）")
+	rootCmd.PersistentFlags().String("profile", "", "設定ファイルのプロファイル（profiles.<名前>）の既定値を使う")
+	rootCmd.PersistentFlags().Bool("debug", false, "デバッグ情報を表示")
+
+	rootCmd.AddCommand(newStandupCmd())
+	rootCmd.AddCommand(newWeeklyCmd())
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newRunCmd(rootCmd))
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
@@ -35,76 +76,522 @@ func main() {
 
 func runRoot(cmd *cobra.Command) error {
 	org, _ := cmd.Flags().GetString("org")
-	repo, _ := cmd.Flags().GetString("repo")
 	format, _ := cmd.Flags().GetString("format")
 	since, _ := cmd.Flags().GetString("since")
 	until, _ := cmd.Flags().GetString("until")
-	debug, _ := cmd.Flags().GetBool("debug")
+	roleValues, _ := cmd.Flags().GetStringSlice("role")
+	hidePassive, _ := cmd.Flags().GetBool("hide-passive")
+	userValues, _ := cmd.Flags().GetStringSlice("user")
+	team, _ := cmd.Flags().GetString("team")
+	noChecks, _ := cmd.Flags().GetBool("no-checks")
+	noReviewStatus, _ := cmd.Flags().GetBool("no-review-status")
+	showValues, _ := cmd.Flags().GetStringSlice("show")
+	query, _ := cmd.Flags().GetString("query")
+
+	roles, err := client.ParseRoles(roleValues)
+	if err != nil {
+		return err
+	}
+	teamOrg, teamSlug, err := parseTeam(team)
+	if err != nil {
+		return err
+	}
+	show, err := parseShow(showValues)
+	if err != nil {
+		return err
+	}
+	if err := client.ValidateQuery(query); err != nil {
+		return err
+	}
+	tmpl, err := loadTemplate(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOpts, err := parseJSONOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if jsonOpts.enabled() {
+		if tmpl != "" || (cmd.Flags().Changed("format") && format != "json") {
+			return fmt.Errorf("--jsonと--jqはJSON出力でのみ使えます")
+		}
+		format = "json"
+	}
+	webhookURL, _ := cmd.Flags().GetString("post")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if webhookURL != "" {
+		if tmpl != "" || jsonOpts.enabled() || (cmd.Flags().Changed("format") && format != "slack") {
+			return fmt.Errorf("--postはSlack形式の出力でのみ使えます")
+		}
+		format = "slack"
+	} else if dryRun {
+		return fmt.Errorf("--dry-runは--postと一緒に指定してください")
+	}
 
-	c, err := client.NewPRClient()
+	loc, err := resolveLocation(cmd)
+	if err != nil {
+		return err
+	}
+	rng, err := client.ParseDateRange(since, until, loc)
 	if err != nil {
 		return err
 	}
 
-	c.SetDebug(debug)
-	prs, err := c.FetchTodaysPRs(org, repo, since, until)
+	filters, err := parseFilters(cmd)
+	if err != nil {
+		return err
+	}
+
+	repos, repoHost, err := resolveRepos(cmd)
+	if err != nil {
+		return err
+	}
+	clients, err := newClients(cmd, repoHost)
+	if err != nil {
+		return err
+	}
+
+	opts := client.SearchOptions{
+		Org:              org,
+		Repos:            repos,
+		Filters:          filters,
+		Query:            query,
+		Range:            rng,
+		Roles:            roles,
+		WithChecks:       !noChecks,
+		WithReviewStatus: !noReviewStatus,
+	}
+	// チームのメンバーはホストごとに取得し、そのホストで検索する
+	users := uniqueUsers(userValues)
+	prs, err := clients.fetch(func(c *client.PRClient) ([]client.PullRequest, error) {
+		hostUsers := uniqueUsers(userValues)
+		if teamSlug != "" {
+			members, err := c.FetchTeamMembers(teamOrg, teamSlug)
+			if err != nil {
+				return nil, err
+			}
+			if len(members) == 0 {
+				return nil, fmt.Errorf("チーム %s にメンバーがいません", team)
+			}
+			hostUsers = uniqueUsers(append(hostUsers, members...))
+			users = uniqueUsers(append(users, members...))
+		}
+		if len(hostUsers) > 0 {
+			return c.FetchUsersPRs(opts, hostUsers)
+		}
+		return c.FetchTodaysPRs(opts)
+	})
+	if err != nil {
+		return err
+	}
+	printDebugSummary(cmd, clients)
+	printWarnings(clients)
+
+	if hidePassive {
+		prs = activePRs(prs)
+	}
+	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
+	applySizes(prs, cfg.Sizes())
+
+	d := digest{
+		PRs:      prs,
+		Failures: clients.Errors(),
+		Range:    rng,
+		Ranged:   since != "" || until != "",
+		Roles:    roles,
+		Users:    users,
+		Show:     show,
+	}
+	if tmpl != "" {
+		return outputTemplate(os.Stdout, tmpl, d)
+	}
 
 	switch format {
 	case "json":
-		return outputJSON(prs)
+		return outputJSON(os.Stdout, d.PRs, d.Failures, jsonOpts)
+	case "markdown":
+		outputMarkdown(os.Stdout, d)
+		if len(d.Failures) > 0 {
+			fmt.Println()
+			printFailures(os.Stdout, d.Failures)
+		}
+		return nil
+	case "slack":
+		msg := buildSlackMessage(d)
+		if webhookURL == "" || dryRun {
+			return outputSlack(os.Stdout, msg)
+		}
+		if err := postSlack(webhookURL, msg); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Slackに投稿しました")
+		return nil
+	case "text":
+		if err := outputText(d); err != nil {
+			return err
+		}
+		printFailures(os.Stdout, d.Failures)
+		return nil
 	default:
-		return outputText(prs, since, until)
+		return fmt.Errorf("不明な出力形式: %s（text/json/markdown/slackのいずれかを指定してください）", format)
+	}
+}
+
+// newClient は共通のフラグを反映したhostのクライアントを作成する
+func newClient(cmd *cobra.Command, host string) (*client.PRClient, error) {
+	backend, _ := cmd.Flags().GetString("backend")
+	debug, _ := cmd.Flags().GetBool("debug")
+	strict, _ := cmd.Flags().GetBool("strict")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+
+	opts := client.ClientOptions{Host: host, MaxConcurrency: maxConcurrency}
+	if !noCache {
+		opts.CacheDir = config.CacheDir()
+	}
+	c, err := client.NewPRClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.SetDebug(debug)
+	c.SetStrict(strict)
+	if err := c.SetBackend(backend); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// resolveLocation は--tzフラグ（省略時は設定ファイルの値）のタイムゾーンを返す。
+// どちらもない場合はローカルタイムゾーンを使う
+func resolveLocation(cmd *cobra.Command) (*time.Location, error) {
+	tz, _ := cmd.Flags().GetString("tz")
+	return config.LoadLocation(tz)
+}
+
+// debugf は--debug指定時にメッセージを表示する
+func debugf(cmd *cobra.Command, format string, args ...interface{}) {
+	if debug, _ := cmd.Flags().GetBool("debug"); debug {
+		fmt.Printf(format, args...)
+	}
+}
+
+// printDebugSummary は--debug指定時にレート制限の状況を表示する。
+// JSONなどの出力を壊さないよう標準エラー出力に書く。
+func printDebugSummary(cmd *cobra.Command, clients hostClients) {
+	if debug, _ := cmd.Flags().GetBool("debug"); debug {
+		fmt.Fprint(os.Stderr, clients.RateLimitSummary())
+	}
+}
+
+func printWarnings(clients hostClients) {
+	for _, w := range clients.Warnings() {
+		fmt.Fprintf(os.Stderr, "警告: %s\n", w)
+	}
+}
+
+// activePRs は期間内に自分の活動があったPRだけを返す
+func activePRs(prs []client.PullRequest) []client.PullRequest {
+	var active []client.PullRequest
+	for _, pr := range prs {
+		if !pr.IsPassive() {
+			active = append(active, pr)
+		}
+	}
+	return active
+}
+
+// printFailures は取得に失敗したPRを末尾にまとめて表示する
+func printFailures(w io.Writer, failures []client.PRError) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "⚠️ %d件のPRを取得できませんでした:\n", len(failures))
+	for _, f := range failures {
+		fmt.Fprintf(w, "  - %s\n", f.Error())
+	}
+}
+
+// digest は出力するダイジェストの内容
+type digest struct {
+	PRs      []client.PullRequest
+	Failures []client.PRError
+	Range    client.DateRange
+	// --sinceか--untilで期間を指定した
+	Ranged bool
+	Roles  []client.Role
+	// チームのダイジェストの対象ユーザー（空の場合は自分のダイジェスト）
+	Users []string
+	// テキスト出力に表示する詳細
+	Show showOptions
+}
+
+// showOptions は--showで指定されたテキスト出力の詳細
+type showOptions struct {
+	labels bool
+	issues bool
+}
+
+// parseShow は--showの値を検証する
+func parseShow(values []string) (showOptions, error) {
+	var show showOptions
+	for _, value := range values {
+		switch strings.TrimSpace(value) {
+		case "labels":
+			show.labels = true
+		case "issues":
+			show.issues = true
+		default:
+			return show, fmt.Errorf("不明な詳細: %s（labels/issuesのいずれかを指定してください）", value)
+		}
 	}
+	return show, nil
+}
+
+// team はチームのダイジェストかどうかを返す
+func (d digest) team() bool {
+	return len(d.Users) > 0
 }
 
-func outputJSON(prs []client.PullRequest) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(prs)
+// title は出力の見出しを返す
+func (d digest) title() string {
+	whose := "Your"
+	if d.team() {
+		whose = "Team"
+	}
+	if d.Ranged {
+		return fmt.Sprintf("%s Pull Requests (%s)", whose, rangeLabel(d.Range))
+	}
+	return fmt.Sprintf("%s Pull Requests Updated Today (%s)", whose, d.Range.Since.Format("2006-01-02"))
 }
 
-func outputText(prs []client.PullRequest, since, until string) error {
-	if len(prs) == 0 {
-		if since != "" || until != "" {
-			fmt.Printf("指定期間（%s 〜 %s）に作成または更新したPRはありません\n",
-				since, until)
+func outputText(d digest) error {
+	if len(d.PRs) == 0 {
+		who := ""
+		if d.team() {
+			who = "指定したユーザーが"
+		}
+		if d.Ranged {
+			fmt.Printf("指定期間（%s）に%s作成または更新したPRはありません\n", rangeLabel(d.Range), who)
 		} else {
-			fmt.Println("今日作成または更新したPRはありません")
+			fmt.Printf("今日%s作成または更新したPRはありません\n", who)
 		}
 		return nil
 	}
 
-	if since != "" || until != "" {
-		fmt.Printf("Your Pull Requests (%s 〜 %s):\n\n",
-			since, until)
+	fmt.Printf("%s:\n\n", d.title())
+
+	// チームのダイジェストは人ごとにまとめて表示
+	if !d.team() {
+		printPRs(d.PRs, d)
 	} else {
-		fmt.Printf("Your Pull Requests Updated Today (%s):\n\n",
-			time.Now().Format("2006-01-02"))
+		users, byUser := groupByUser(d.PRs, d.Users)
+		for _, user := range users {
+			fmt.Printf("@%s (%d件)\n\n", user, len(byUser[user]))
+			printPRs(byUser[user], d)
+		}
 	}
 
-	for _, pr := range prs {
-		// ステータスに応じて表示を変更
-		stateStr := ""
-		if pr.Merged {
-			stateStr = "🟣" // 紫：マージ済み
-		} else if pr.State == "closed" {
-			stateStr = "🔴" // 赤：クローズ
-		} else if pr.Draft {
-			stateStr = "⚪️" // 白：ドラフト
-		} else {
-			stateStr = "🟢" // 緑：オープン
+	fmt.Printf("合計: %d件 / %s\n\n", len(d.PRs), sumDiffStats(d.PRs))
+	return nil
+}
+
+// printPRs はPRを表示する。複数の関係を指定した場合は関係ごとにまとめる
+func printPRs(prs []client.PullRequest, d digest) {
+	roles := d.Roles
+	if len(roles) <= 1 {
+		for _, pr := range prs {
+			printPR(pr, d.Show)
+		}
+		return
+	}
+
+	for _, role := range roles {
+		var section []client.PullRequest
+		for _, pr := range prs {
+			if primaryRole(pr, roles) == role {
+				section = append(section, pr)
+			}
+		}
+		if len(section) == 0 {
+			continue
 		}
 
-		// fmt.Printf("%s [%s] %s (#%d)\n", stateStr, pr.Repository.FullName, pr.Title, pr.Number)
-		fmt.Printf("%s %s\n", stateStr, pr.Title)
-		// fmt.Printf("Created: %s, Updated: %s\n",
-		// 	pr.CreatedAt.Format("2006-01-02 15:04"),
-		// 	pr.UpdatedAt.Format("2006-01-02 15:04"))
-		fmt.Printf("%s\n\n", pr.HTMLURL)
+		fmt.Printf("[%s] %d件\n\n", roleTitle(role), len(section))
+		for _, pr := range section {
+			printPR(pr, d.Show)
+		}
 	}
+}
 
-	return nil
+// rangeLabel は相対指定を解決した後の期間を表示用の文字列にする
+func rangeLabel(rng client.DateRange) string {
+	last := rng.LastDay().Format("2006-01-02")
+	if rng.Since.IsZero() {
+		return "〜 " + last
+	}
+	return rng.Since.Format("2006-01-02") + " 〜 " + last
+}
+
+func printPR(pr client.PullRequest, show showOptions) {
+	// ステータスに応じて表示を変更
+	stateStr := stateEmoji(pr) + checkEmoji(pr)
+
+	// fmt.Printf("%s [%s] %s (#%d)\n", stateStr, pr.Repository.FullName, pr.Title, pr.Number)
+	fmt.Printf("%s %s [%s]\n", stateStr, pr.Title, sizeSummary(pr))
+	// fmt.Printf("Created: %s, Updated: %s\n",
+	// 	pr.CreatedAt.Format("2006-01-02 15:04"),
+	// 	pr.UpdatedAt.Format("2006-01-02 15:04"))
+	if pr.ReviewState != "" || pr.CommentCount > 0 {
+		fmt.Printf("%s\n", reviewSummary(pr))
+	}
+	if line := reviewerLine(pr); line != "" {
+		fmt.Printf("%s\n", line)
+	}
+	if len(pr.FailingChecks) > 0 {
+		fmt.Printf("  失敗したチェック: %s\n", strings.Join(pr.FailingChecks, ", "))
+	}
+	for _, line := range detailLines(pr, show) {
+		fmt.Printf("%s\n", line)
+	}
+	fmt.Printf("%s\n\n", pr.HTMLURL)
+}
+
+func stateEmoji(pr client.PullRequest) string {
+	if pr.Merged {
+		return "🟣" // 紫：マージ済み
+	} else if pr.State == "closed" {
+		return "🔴" // 赤：クローズ
+	} else if pr.Draft {
+		return "⚪️" // 白：ドラフト
+	}
+	return "🟢" // 緑：オープン
+}
+
+// reviewerLine はオープンなPRのレビュー状態、承認した人、レビュー待ちの人を1行にまとめる。
+// レビュー状況を取得していない場合は空文字列を返す
+func reviewerLine(pr client.PullRequest) string {
+	if pr.State != "open" || pr.ReviewDecision == "" {
+		return ""
+	}
+
+	var parts []string
+	switch pr.ReviewDecision {
+	case "APPROVED":
+		parts = append(parts, "✅ 承認済み")
+	case "CHANGES_REQUESTED":
+		parts = append(parts, "🔁 変更依頼あり")
+	default:
+		parts = append(parts, "👀 レビュー待ち")
+	}
+	if len(pr.Approvers) > 0 {
+		parts = append(parts, "承認: "+mentions(pr.Approvers))
+	}
+	if len(pr.RequestedReviewers) > 0 {
+		parts = append(parts, "未レビュー: "+mentions(pr.RequestedReviewers))
+	}
+	return "  レビュアー: " + strings.Join(parts, " / ")
+}
+
+func mentions(users []string) string {
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = "@" + user
+	}
+	return strings.Join(names, ", ")
+}
+
+// detailLines は--showで指定されたラベル・マイルストーン・担当者とクローズするIssueの行を返す
+func detailLines(pr client.PullRequest, show showOptions) []string {
+	var lines []string
+	if show.labels {
+		if len(pr.Labels) > 0 {
+			names := make([]string, len(pr.Labels))
+			for i, label := range pr.Labels {
+				names[i] = label.Name
+			}
+			lines = append(lines, "  ラベル: "+strings.Join(names, ", "))
+		}
+		if pr.Milestone != "" {
+			lines = append(lines, "  マイルストーン: "+pr.Milestone)
+		}
+		if len(pr.Assignees) > 0 {
+			lines = append(lines, "  担当者: "+mentions(pr.Assignees))
+		}
+	}
+	if show.issues && len(pr.ClosingIssues) > 0 {
+		refs := make([]string, len(pr.ClosingIssues))
+		for i, ref := range pr.ClosingIssues {
+			// 同じリポジトリのIssueは番号だけにする
+			if ref.Repository == pr.Repository.FullName {
+				refs[i] = fmt.Sprintf("#%d", ref.Number)
+			} else {
+				refs[i] = ref.String()
+			}
+		}
+		lines = append(lines, "  クローズするIssue: "+strings.Join(refs, ", "))
+	}
+	return lines
+}
+
+// checkEmoji はCIの状態を表す絵文字を返す。取得していない場合は空文字列を返す
+func checkEmoji(pr client.PullRequest) string {
+	switch pr.CheckState {
+	case client.CheckStateSuccess:
+		return "✅"
+	case client.CheckStateFailure:
+		return "❌"
+	case client.CheckStatePending:
+		return "⏳"
+	}
+	return ""
+}
+
+// primaryRole は指定した関係の順で最初に一致したものを返す
+func primaryRole(pr client.PullRequest, roles []client.Role) client.Role {
+	for _, role := range roles {
+		for _, r := range pr.Roles {
+			if r == role {
+				return role
+			}
+		}
+	}
+	return roles[0]
+}
+
+func roleTitle(role client.Role) string {
+	switch role {
+	case client.RoleReviewer:
+		return "レビューしたPR"
+	case client.RoleCommenter:
+		return "コメントしたPR"
+	case client.RoleAssignee:
+		return "アサインされたPR"
+	case client.RoleInvolves:
+		return "関係するPR"
+	default:
+		return "作成したPR"
+	}
+}
+
+// reviewSummary は自分のレビュー状態とコメント数を1行にまとめる
+func reviewSummary(pr client.PullRequest) string {
+	var parts []string
+	switch pr.ReviewState {
+	case "APPROVED":
+		parts = append(parts, "✅ 承認")
+	case "CHANGES_REQUESTED":
+		parts = append(parts, "🔁 変更依頼")
+	case "COMMENTED":
+		parts = append(parts, "💬 コメント")
+	case "DISMISSED":
+		parts = append(parts, "🚫 却下")
+	}
+	if pr.CommentCount > 0 {
+		parts = append(parts, fmt.Sprintf("コメント %d件", pr.CommentCount))
+	}
+	return "  レビュー: " + strings.Join(parts, " / ")
 }
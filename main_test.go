@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestReviewerLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		pr       client.PullRequest
+		expected string
+	}{
+		{
+			name:     "レビュー状況を取得していない",
+			pr:       client.PullRequest{State: "open"},
+			expected: "",
+		},
+		{
+			name:     "クローズ済み",
+			pr:       client.PullRequest{State: "closed", ReviewDecision: "APPROVED"},
+			expected: "",
+		},
+		{
+			name: "承認済みでレビュー待ちの人がいる",
+			pr: client.PullRequest{
+				State:              "open",
+				ReviewDecision:     "APPROVED",
+				Approvers:          []string{"alice"},
+				RequestedReviewers: []string{"bob", "owner/backend"},
+			},
+			expected: "  レビュアー: ✅ 承認済み / 承認: @alice / 未レビュー: @bob, @owner/backend",
+		},
+		{
+			name:     "レビュー待ち",
+			pr:       client.PullRequest{State: "open", ReviewDecision: "REVIEW_REQUIRED"},
+			expected: "  レビュアー: 👀 レビュー待ち",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reviewerLine(tt.pr); got != tt.expected {
+				t.Errorf("reviewerLine() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetailLines(t *testing.T) {
+	pr := client.PullRequest{
+		Labels:        []client.Label{{Name: "bug", Color: "d73a4a"}, {Name: "backend", Color: "0e8a16"}},
+		Milestone:     "v1.0",
+		Assignees:     []string{"alice"},
+		ClosingIssues: []client.IssueRef{{Repository: "owner/repo", Number: 12}, {Repository: "other/lib", Number: 3}},
+	}
+	pr.Repository.FullName = "owner/repo"
+
+	tests := []struct {
+		name     string
+		show     showOptions
+		expected []string
+	}{
+		{name: "未指定", show: showOptions{}, expected: nil},
+		{
+			name:     "ラベル",
+			show:     showOptions{labels: true},
+			expected: []string{"  ラベル: bug, backend", "  マイルストーン: v1.0", "  担当者: @alice"},
+		},
+		{
+			name:     "クローズするIssue",
+			show:     showOptions{issues: true},
+			expected: []string{"  クローズするIssue: #12, other/lib#3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detailLines(pr, tt.show); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("detailLines() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseShow(t *testing.T) {
+	show, err := parseShow([]string{"labels", " issues"})
+	if err != nil || !show.labels || !show.issues {
+		t.Errorf("parseShow() = %+v, %v, want labels and issues", show, err)
+	}
+	if _, err := parseShow([]string{"reviews"}); err == nil {
+		t.Error("parseShow() error = nil, want error")
+	}
+}
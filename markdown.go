@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+// outputMarkdown はPRをリポジトリごとにまとめてMarkdownで出力する。
+// GitHubのIssueやNotion、Wikiにそのまま貼り付けられる形にする。
+// チームのダイジェストは人ごとに分けてからリポジトリごとにまとめる。
+func outputMarkdown(w io.Writer, d digest) {
+	fmt.Fprintf(w, "## %s\n\n", d.title())
+	if len(d.PRs) == 0 {
+		fmt.Fprintln(w, "PRはありません")
+		return
+	}
+
+	if !d.team() {
+		writeMarkdownRepositories(w, d.PRs, "###")
+	} else {
+		users, byUser := groupByUser(d.PRs, d.Users)
+		for i, user := range users {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "### @%s (%d)\n\n", user, len(byUser[user]))
+			writeMarkdownRepositories(w, byUser[user], "####")
+		}
+	}
+
+	fmt.Fprintf(w, "\n**合計**: %d件 / %s\n", len(d.PRs), sumDiffStats(d.PRs))
+}
+
+// writeMarkdownRepositories はリポジトリごとの見出しとPRの一覧を出力する
+func writeMarkdownRepositories(w io.Writer, prs []client.PullRequest, heading string) {
+	repos, byRepo := groupByRepository(prs)
+	for i, repo := range repos {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s %s (%d)\n\n", heading, repo, len(byRepo[repo]))
+		for _, pr := range byRepo[repo] {
+			line := fmt.Sprintf("- %s [%s](%s) #%d `%s`", stateEmoji(pr), markdownEscape(pr.Title), pr.HTMLURL, pr.Number, sizeSummary(pr))
+			if pr.ReviewState != "" || pr.CommentCount > 0 {
+				line += " — " + strings.TrimSpace(reviewSummary(pr))
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// groupByRepository はPRをリポジトリごとに分け、リポジトリ名の順に並べる
+func groupByRepository(prs []client.PullRequest) ([]string, map[string][]client.PullRequest) {
+	byRepo := make(map[string][]client.PullRequest)
+	var repos []string
+	for _, pr := range prs {
+		repo := repositoryName(pr)
+		if _, ok := byRepo[repo]; !ok {
+			repos = append(repos, repo)
+		}
+		byRepo[repo] = append(byRepo[repo], pr)
+	}
+	sort.Strings(repos)
+	return repos, byRepo
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestOutputMarkdown(t *testing.T) {
+	newPR := func(repo string, number int, title, state string, merged bool) client.PullRequest {
+		pr := client.PullRequest{
+			Title:   title,
+			HTMLURL: "https://github.com/" + repo + "/pull/1",
+			State:   state,
+			Merged:  merged,
+			Number:  number,
+			// 変更量はPR番号から決める
+			Additions:    number * 10,
+			Deletions:    number,
+			ChangedFiles: 1,
+			Commits:      1,
+			Size:         "S",
+		}
+		pr.Repository.FullName = repo
+		return pr
+	}
+
+	withUser := func(pr client.PullRequest, user string) client.PullRequest {
+		pr.User = user
+		return pr
+	}
+
+	rng := client.DateRange{
+		Since: time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 1, 27, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		prs      []client.PullRequest
+		users    []string
+		expected string
+	}{
+		{
+			name: "リポジトリごとにまとめる",
+			prs: []client.PullRequest{
+				newPR("owner/web", 3, "画面の修正", "open", false),
+				newPR("owner/api", 1, "[API] 新機能の追加", "closed", true),
+				newPR("owner/web", 2, "不要なコードの削除", "closed", false),
+			},
+			expected: "## Your Pull Requests (2024-01-22 〜 2024-01-26)\n\n" +
+				"### owner/api (1)\n\n" +
+				"- 🟣 [\\[API\\] 新機能の追加](https://github.com/owner/api/pull/1) #1 `S +10 -1`\n" +
+				"\n" +
+				"### owner/web (2)\n\n" +
+				"- 🟢 [画面の修正](https://github.com/owner/web/pull/1) #3 `S +30 -3`\n" +
+				"- 🔴 [不要なコードの削除](https://github.com/owner/web/pull/1) #2 `S +20 -2`\n" +
+				"\n" +
+				"**合計**: 3件 / +60 -6 / 3ファイル / 3コミット\n",
+		},
+		{
+			name: "チームは人ごとにまとめる",
+			prs: []client.PullRequest{
+				withUser(newPR("owner/web", 3, "画面の修正", "open", false), "bob"),
+				withUser(newPR("owner/api", 1, "新機能の追加", "closed", true), "alice"),
+			},
+			users: []string{"alice", "bob", "carol"},
+			expected: "## Team Pull Requests (2024-01-22 〜 2024-01-26)\n\n" +
+				"### @alice (1)\n\n" +
+				"#### owner/api (1)\n\n" +
+				"- 🟣 [新機能の追加](https://github.com/owner/api/pull/1) #1 `S +10 -1`\n" +
+				"\n" +
+				"### @bob (1)\n\n" +
+				"#### owner/web (1)\n\n" +
+				"- 🟢 [画面の修正](https://github.com/owner/web/pull/1) #3 `S +30 -3`\n" +
+				"\n" +
+				"**合計**: 2件 / +40 -4 / 2ファイル / 2コミット\n",
+		},
+		{
+			name:     "PRがない場合",
+			expected: "## Your Pull Requests (2024-01-22 〜 2024-01-26)\n\nPRはありません\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			outputMarkdown(&buf, digest{PRs: tt.prs, Range: rng, Ranged: true, Users: tt.users})
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("outputMarkdown() =\n%s\nwant\n%s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/spf13/cobra"
+)
+
+// resolveRepos は検索するリポジトリと、カレントディレクトリから推測した場合はそのホストを返す。
+// --repo、--org、--allのいずれも指定しない場合はカレントディレクトリのgitのリモートからリポジトリを推測し、
+// 推測できない場合（gitのリポジトリでないなど）はすべてのリポジトリを検索する
+func resolveRepos(cmd *cobra.Command) ([]string, string, error) {
+	values, _ := cmd.Flags().GetStringSlice("repo")
+	org, _ := cmd.Flags().GetString("org")
+	all, _ := cmd.Flags().GetBool("all")
+
+	// --allを明示した場合は設定ファイルのrepoも使わない
+	if cmd.Flags().Changed("all") && all {
+		return nil, "", nil
+	}
+	var repos []string
+	for _, value := range values {
+		repo := strings.TrimSpace(value)
+		if repo == "" {
+			continue
+		}
+		if err := client.ValidateRepo(repo); err != nil {
+			return nil, "", err
+		}
+		repos = append(repos, repo)
+	}
+	if len(repos) > 0 || org != "" || all {
+		return repos, "", nil
+	}
+
+	r, err := currentRepository()
+	if err != nil {
+		debugf(cmd, "カレントディレクトリのリポジトリを推測できません: %v\n", err)
+		return nil, "", nil
+	}
+	// --hostnameで別のホストを指定した場合は推測したリポジトリを使わない
+	hostValues, _ := cmd.Flags().GetStringSlice("hostname")
+	hosts, err := parseHostnames(hostValues)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(hosts) > 0 && !containsFold(hosts, r.Host()) {
+		return nil, "", nil
+	}
+
+	repo := r.Owner() + "/" + r.Name()
+	debugf(cmd, "カレントディレクトリのリポジトリ: %s/%s\n", r.Host(), repo)
+	return []string{repo}, r.Host(), nil
+}
+
+// currentRepository はカレントディレクトリのリポジトリを返す。
+// ghと同じくGH_REPO、gh repo set-defaultで選んだリポジトリ、リモート（upstream、github、originの順）の順に使う
+func currentRepository() (repository.Repository, error) {
+	if os.Getenv("GH_REPO") == "" {
+		out, err := exec.Command("git", "config", "--get-regexp", `^remote\..*\.gh-resolved$`).Output()
+		if err == nil {
+			if r, ok := resolvedRepository(string(out), remoteURL); ok {
+				return r, nil
+			}
+		}
+	}
+	return gh.CurrentRepository()
+}
+
+func remoteURL(name string) (string, error) {
+	out, err := exec.Command("git", "remote", "get-url", name).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// resolvedRepository はgit configのremote.<名前>.gh-resolvedからgh repo set-defaultで選んだリポジトリを返す。
+// 値がbaseの場合はそのリモートのリポジトリ、owner/repoの場合はそのリポジトリを使う
+func resolvedRepository(gitConfig string, remoteURL func(name string) (string, error)) (repository.Repository, bool) {
+	for _, line := range strings.Split(strings.TrimSpace(gitConfig), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".gh-resolved")
+		u, err := remoteURL(name)
+		if err != nil {
+			continue
+		}
+		remote, err := repository.Parse(u)
+		if err != nil {
+			continue
+		}
+		if value == "base" {
+			return remote, true
+		}
+		if r, err := repository.ParseWithHost(value, remote.Host()); err == nil {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
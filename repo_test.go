@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResolvedRepository(t *testing.T) {
+	remotes := map[string]string{
+		"origin":   "git@github.com:me/widgets.git",
+		"upstream": "https://ghe.example.com/acme/widgets.git",
+	}
+	remoteURL := func(name string) (string, error) {
+		if u, ok := remotes[name]; ok {
+			return u, nil
+		}
+		return "", fmt.Errorf("no such remote: %s", name)
+	}
+
+	tests := []struct {
+		name      string
+		gitConfig string
+		want      string
+		wantOK    bool
+	}{
+		{
+			name:      "リモートのリポジトリ",
+			gitConfig: "remote.upstream.gh-resolved base\n",
+			want:      "ghe.example.com/acme/widgets",
+			wantOK:    true,
+		},
+		{
+			name:      "リポジトリを指定",
+			gitConfig: "remote.origin.gh-resolved acme/widgets\n",
+			want:      "github.com/acme/widgets",
+			wantOK:    true,
+		},
+		{
+			name:      "存在しないリモート",
+			gitConfig: "remote.fork.gh-resolved base\n",
+		},
+		{
+			name:      "設定なし",
+			gitConfig: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := resolvedRepository(tt.gitConfig, remoteURL)
+			if ok != tt.wantOK {
+				t.Fatalf("resolvedRepository() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := r.Host() + "/" + r.Owner() + "/" + r.Name(); got != tt.want {
+				t.Errorf("resolvedRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hiroyannnn/gh-pr-digest/config"
+	"github.com/spf13/cobra"
+)
+
+// newRunCmd は設定ファイルのqueriesに保存した検索の条件でダイジェストを表示するコマンドを作成する。
+// ルートコマンドと同じフラグ（--sinceや--formatなど）を使えるように、ルートのフラグを共有する
+func newRunCmd(root *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [name]",
+		Short: "Show the digest for a saved query",
+		Long: `設定ファイルの queries に保存した検索の条件でダイジェストを表示します。
+期間（--since/--until）や自分との関係（--role）は通常どおり指定でき、--query の条件も追加できます。
+名前を省略すると保存したクエリの一覧を表示します。`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				for _, name := range cfg.QueryNames() {
+					fmt.Printf("%s: %s\n", name, cfg.Queries[name])
+				}
+				return nil
+			}
+
+			saved, err := cfg.Query(args[0])
+			if err != nil {
+				return err
+			}
+			query, _ := cmd.Flags().GetString("query")
+			if err := cmd.Flags().Set("query", strings.TrimSpace(saved+" "+query)); err != nil {
+				return err
+			}
+			return runRoot(cmd)
+		},
+	}
+	cmd.Flags().AddFlagSet(root.LocalNonPersistentFlags())
+	return cmd
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/hiroyannnn/gh-pr-digest/config"
+)
+
+// sizeLabel は追加と削除の行数の合計からPRのサイズのラベルを決める
+func sizeLabel(pr client.PullRequest, sizes config.SizeThresholds) string {
+	switch lines := pr.Additions + pr.Deletions; {
+	case lines < sizes.XS:
+		return "XS"
+	case lines < sizes.S:
+		return "S"
+	case lines < sizes.M:
+		return "M"
+	case lines < sizes.L:
+		return "L"
+	default:
+		return "XL"
+	}
+}
+
+// applySizes は各PRにサイズのラベルを設定する
+func applySizes(prs []client.PullRequest, sizes config.SizeThresholds) {
+	for i := range prs {
+		prs[i].Size = sizeLabel(prs[i], sizes)
+	}
+}
+
+// diffStats はPRの変更量
+type diffStats struct {
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+	Commits      int `json:"commits"`
+}
+
+func (s *diffStats) add(pr client.PullRequest) {
+	s.Additions += pr.Additions
+	s.Deletions += pr.Deletions
+	s.ChangedFiles += pr.ChangedFiles
+	s.Commits += pr.Commits
+}
+
+// sumDiffStats はPRの変更量を合計する
+func sumDiffStats(prs []client.PullRequest) diffStats {
+	var total diffStats
+	for _, pr := range prs {
+		total.add(pr)
+	}
+	return total
+}
+
+func (s diffStats) String() string {
+	return fmt.Sprintf("+%d -%d / %dファイル / %dコミット", s.Additions, s.Deletions, s.ChangedFiles, s.Commits)
+}
+
+// sizeSummary は"M +120 -30"のようにPRのサイズと変更行数を返す
+func sizeSummary(pr client.PullRequest) string {
+	lines := fmt.Sprintf("+%d -%d", pr.Additions, pr.Deletions)
+	if pr.Size == "" {
+		return lines
+	}
+	return pr.Size + " " + lines
+}
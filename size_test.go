@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/hiroyannnn/gh-pr-digest/config"
+)
+
+func TestSizeLabel(t *testing.T) {
+	sizes := config.SizeThresholds{XS: 10, S: 100, M: 500, L: 1000}
+
+	tests := []struct {
+		name      string
+		additions int
+		deletions int
+		expected  string
+	}{
+		{name: "変更なし", expected: "XS"},
+		{name: "XSの上限", additions: 5, deletions: 4, expected: "XS"},
+		{name: "追加と削除の合計で判定", additions: 5, deletions: 5, expected: "S"},
+		{name: "M", additions: 300, expected: "M"},
+		{name: "L", additions: 400, deletions: 200, expected: "L"},
+		{name: "閾値以上はXL", additions: 1000, expected: "XL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := client.PullRequest{Additions: tt.additions, Deletions: tt.deletions}
+			if got := sizeLabel(pr, sizes); got != tt.expected {
+				t.Errorf("sizeLabel() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
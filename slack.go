@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Slackのsectionブロックのテキストの上限
+	slackSectionLimit = 3000
+	// 1つのメッセージに含められるブロック数の上限
+	slackMaxBlocks = 50
+)
+
+// slackMessage はIncoming Webhookに送るBlock Kitのメッセージ
+type slackMessage struct {
+	// 通知に表示されるテキスト
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// buildSlackMessage は見出し、リポジトリごとのセクション、期間のコンテキストからなるメッセージを作る。
+// チームのダイジェストは人ごとにセクションを分ける。
+func buildSlackMessage(d digest) slackMessage {
+	title := d.title()
+	msg := slackMessage{
+		Text: fmt.Sprintf("%s: %d件", title, len(d.PRs)),
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: title, Emoji: true}},
+		},
+	}
+
+	if len(d.PRs) == 0 {
+		msg.Blocks = append(msg.Blocks, slackSection("PRはありません"))
+	}
+
+	if d.team() {
+		users, byUser := groupByUser(d.PRs, d.Users)
+		for _, user := range users {
+			lines := []string{fmt.Sprintf("*@%s* (%d)", slackEscape(user), len(byUser[user]))}
+			for _, pr := range byUser[user] {
+				lines = append(lines, fmt.Sprintf("%s <%s|%s> %s", stateEmoji(pr), pr.HTMLURL, slackEscape(pr.Title), slackEscape(prRef(pr))))
+			}
+			msg.Blocks = append(msg.Blocks, slackSections(lines)...)
+		}
+	} else {
+		repos, byRepo := groupByRepository(d.PRs)
+		for _, repo := range repos {
+			lines := []string{fmt.Sprintf("*%s* (%d)", slackEscape(repo), len(byRepo[repo]))}
+			for _, pr := range byRepo[repo] {
+				lines = append(lines, fmt.Sprintf("%s <%s|%s> #%d", stateEmoji(pr), pr.HTMLURL, slackEscape(pr.Title), pr.Number))
+			}
+			msg.Blocks = append(msg.Blocks, slackSections(lines)...)
+		}
+	}
+
+	// 末尾のコンテキストと省略の案内の分を残す
+	if len(msg.Blocks) > slackMaxBlocks-1 {
+		msg.Blocks = append(msg.Blocks[:slackMaxBlocks-2], slackSection("（多すぎるため以降は省略しました）"))
+	}
+
+	footer := []slackText{{Type: "mrkdwn", Text: "期間: " + rangeLabel(d.Range)}}
+	if len(d.Failures) > 0 {
+		footer = append(footer, slackText{Type: "mrkdwn", Text: fmt.Sprintf("⚠️ %d件のPRを取得できませんでした", len(d.Failures))})
+	}
+	msg.Blocks = append(msg.Blocks, slackBlock{Type: "context", Elements: footer})
+	return msg
+}
+
+// slackSections は行をセクションにまとめる。上限を超える場合は複数のセクションに分ける
+func slackSections(lines []string) []slackBlock {
+	var blocks []slackBlock
+	var text string
+	for _, line := range lines {
+		if text != "" && len(text)+len(line)+1 > slackSectionLimit {
+			blocks = append(blocks, slackSection(text))
+			text = ""
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += line
+	}
+	return append(blocks, slackSection(text))
+}
+
+func slackSection(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}
+}
+
+func outputSlack(w io.Writer, msg slackMessage) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(msg)
+}
+
+const (
+	slackMaxAttempts = 3
+	slackRetryDelay  = time.Second
+)
+
+// テスト用に待機をモック可能にする
+var slackSleep = time.Sleep
+
+// postSlack はメッセージをIncoming Webhookに送る。
+// レート制限（429）とサーバーエラーの場合は待機してリトライする。
+func postSlack(webhookURL string, msg slackMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("Slackのメッセージの作成に失敗: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	for attempt := 1; ; attempt++ {
+		resp, err := httpClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			if attempt >= slackMaxAttempts {
+				return fmt.Errorf("Slackへの投稿に失敗: %w", err)
+			}
+			slackSleep(slackRetryDelay << (attempt - 1))
+			continue
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			return nil
+		}
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt >= slackMaxAttempts {
+			return fmt.Errorf("Slackへの投稿に失敗: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		}
+
+		delay := slackRetryDelay << (attempt - 1)
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			delay = time.Duration(secs) * time.Second
+		}
+		slackSleep(delay)
+	}
+}
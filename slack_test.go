@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestBuildSlackMessage(t *testing.T) {
+	newPR := func(repo string, number int, title string) client.PullRequest {
+		pr := client.PullRequest{Title: title, HTMLURL: "https://github.com/" + repo + "/pull/1", State: "open", Number: number}
+		pr.Repository.FullName = repo
+		return pr
+	}
+	rng := client.DateRange{
+		Since: time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 1, 27, 0, 0, 0, 0, time.UTC),
+	}
+	prs := []client.PullRequest{
+		newPR("owner/web", 2, "<script> & more"),
+		newPR("owner/api", 1, "新機能の追加"),
+	}
+	failures := []client.PRError{{Repository: "owner/sso", Number: 3, Message: "HTTP 403"}}
+
+	msg := buildSlackMessage(digest{PRs: prs, Failures: failures, Range: rng, Ranged: true})
+
+	expected := []struct {
+		blockType string
+		text      string
+	}{
+		{blockType: "header", text: "Your Pull Requests (2024-01-22 〜 2024-01-26)"},
+		{blockType: "section", text: "*owner/api* (1)\n🟢 <https://github.com/owner/api/pull/1|新機能の追加> #1"},
+		{blockType: "section", text: "*owner/web* (1)\n🟢 <https://github.com/owner/web/pull/1|&lt;script&gt; &amp; more> #2"},
+		{blockType: "context"},
+	}
+	if len(msg.Blocks) != len(expected) {
+		t.Fatalf("blocks = %d, want %d", len(msg.Blocks), len(expected))
+	}
+	for i, want := range expected {
+		block := msg.Blocks[i]
+		if block.Type != want.blockType {
+			t.Errorf("blocks[%d].Type = %s, want %s", i, block.Type, want.blockType)
+		}
+		if want.text != "" && (block.Text == nil || block.Text.Text != want.text) {
+			t.Errorf("blocks[%d].Text = %+v, want %q", i, block.Text, want.text)
+		}
+	}
+	context := msg.Blocks[len(msg.Blocks)-1].Elements
+	if len(context) != 2 || context[0].Text != "期間: 2024-01-22 〜 2024-01-26" {
+		t.Errorf("context = %+v", context)
+	}
+}
+
+func TestPostSlack(t *testing.T) {
+	var delays []time.Duration
+	slackSleep = func(d time.Duration) { delays = append(delays, d) }
+	defer func() { slackSleep = time.Sleep }()
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantRequests int
+		wantDelays   []time.Duration
+	}{
+		{name: "成功", statuses: []int{200}, wantRequests: 1},
+		{name: "レート制限はRetry-Afterだけ待ってリトライ", statuses: []int{429, 200}, wantRequests: 2, wantDelays: []time.Duration{2 * time.Second}},
+		{name: "サーバーエラーは上限までリトライ", statuses: []int{500, 502, 503}, wantErr: true, wantRequests: 3, wantDelays: []time.Duration{time.Second, 2 * time.Second}},
+		{name: "不正なリクエストはリトライしない", statuses: []int{400}, wantErr: true, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays = nil
+			var received []slackMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Content-Type = %s", ct)
+				}
+				var msg slackMessage
+				body, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(body, &msg); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+				received = append(received, msg)
+
+				status := tt.statuses[len(received)-1]
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "2")
+				}
+				w.WriteHeader(status)
+				io.WriteString(w, "ok")
+			}))
+			defer server.Close()
+
+			msg := slackMessage{Text: "digest", Blocks: []slackBlock{slackSection("hello")}}
+			err := postSlack(server.URL, msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("postSlack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(received) != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", len(received), tt.wantRequests)
+			}
+			if received[0].Text != "digest" || received[0].Blocks[0].Text.Text != "hello" {
+				t.Errorf("payload = %+v", received[0])
+			}
+			if fmt.Sprint(delays) != fmt.Sprint(tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/spf13/cobra"
+)
+
+func newStandupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "standup",
+		Short: "Show yesterday/today/blockers for daily standup",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStandup(cmd)
+		},
+	}
+
+	cmd.Flags().String("format", "text", "出力形式（text/markdown/slack）")
+
+	return cmd
+}
+
+// standupReport はスタンドアップで共有する内容
+type standupReport struct {
+	// 前営業日（YYYY-MM-DD形式）
+	Yesterday string
+	// 前営業日に活動したPR
+	Done []client.PullRequest
+	// 作業中のオープン・ドラフトのPR
+	Doing []client.PullRequest
+	// 進められない理由があるPR
+	Blockers []standupBlocker
+}
+
+type standupBlocker struct {
+	PR      client.PullRequest
+	Reasons []string
+}
+
+func runStandup(cmd *cobra.Command) error {
+	org, _ := cmd.Flags().GetString("org")
+	format, _ := cmd.Flags().GetString("format")
+
+	loc, err := resolveLocation(cmd)
+	if err != nil {
+		return err
+	}
+
+	filters, err := parseFilters(cmd)
+	if err != nil {
+		return err
+	}
+
+	repos, repoHost, err := resolveRepos(cmd)
+	if err != nil {
+		return err
+	}
+	clients, err := newClients(cmd, repoHost)
+	if err != nil {
+		return err
+	}
+
+	// 月曜日は前週の金曜日を振り返る
+	yesterdayRange, err := client.ParseDateRange("last-business-day", "last-business-day", loc)
+	if err != nil {
+		return err
+	}
+	done, err := clients.fetch(func(c *client.PRClient) ([]client.PullRequest, error) {
+		return c.FetchTodaysPRs(client.SearchOptions{
+			Org:     org,
+			Repos:   repos,
+			Filters: filters,
+			Range:   yesterdayRange,
+			Roles:   []client.Role{client.RoleAuthor, client.RoleReviewer},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	doing, err := clients.fetch(func(c *client.PRClient) ([]client.PullRequest, error) {
+		return c.FetchTodaysPRs(client.SearchOptions{
+			Org:              org,
+			Repos:            repos,
+			Filters:          filters,
+			Range:            client.Today(loc),
+			Roles:            []client.Role{client.RoleAuthor},
+			OpenOnly:         true,
+			WithChecks:       true,
+			WithReviewStatus: true,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	printDebugSummary(cmd, clients)
+	printWarnings(clients)
+	printFailures(os.Stderr, clients.Errors())
+
+	report := standupReport{
+		Yesterday: yesterdayRange.Since.Format("2006-01-02"),
+		Done:      activePRs(done),
+		Doing:     doing,
+		Blockers:  findBlockers(doing),
+	}
+
+	switch format {
+	case "markdown":
+		renderStandupMarkdown(os.Stdout, report)
+	case "slack":
+		renderStandupSlack(os.Stdout, report)
+	case "text":
+		renderStandupText(os.Stdout, report)
+	default:
+		return fmt.Errorf("不明な出力形式: %s（text/markdown/slackのいずれかを指定してください）", format)
+	}
+	return nil
+}
+
+// findBlockers はレビューで変更を求められた、CIが失敗している、
+// またはレビュアーがいないオープンなPRを抽出する
+func findBlockers(prs []client.PullRequest) []standupBlocker {
+	var blockers []standupBlocker
+	for _, pr := range prs {
+		if pr.State != "open" || pr.Draft {
+			continue
+		}
+
+		var reasons []string
+		if pr.ReviewDecision == "CHANGES_REQUESTED" {
+			reasons = append(reasons, "変更依頼あり")
+		}
+		if pr.CheckState == client.CheckStateFailure {
+			reasons = append(reasons, "CI失敗")
+		}
+		if len(pr.RequestedReviewers) == 0 && len(pr.Reviewers) == 0 {
+			reasons = append(reasons, "レビュアー未設定")
+		}
+		if len(reasons) > 0 {
+			blockers = append(blockers, standupBlocker{PR: pr, Reasons: reasons})
+		}
+	}
+	return blockers
+}
+
+func prRef(pr client.PullRequest) string {
+	return fmt.Sprintf("%s#%d", repositoryName(pr), pr.Number)
+}
+
+func renderStandupText(w io.Writer, r standupReport) {
+	fmt.Fprintf(w, "Yesterday (%s):\n", r.Yesterday)
+	if len(r.Done) == 0 {
+		fmt.Fprintln(w, "- なし")
+	}
+	for _, pr := range r.Done {
+		fmt.Fprintf(w, "- %s %s (%s)\n", stateEmoji(pr), pr.Title, prRef(pr))
+	}
+
+	fmt.Fprintln(w, "\nToday:")
+	if len(r.Doing) == 0 {
+		fmt.Fprintln(w, "- なし")
+	}
+	for _, pr := range r.Doing {
+		fmt.Fprintf(w, "- %s %s (%s)\n", stateEmoji(pr), pr.Title, prRef(pr))
+	}
+
+	fmt.Fprintln(w, "\nBlockers:")
+	if len(r.Blockers) == 0 {
+		fmt.Fprintln(w, "- なし")
+	}
+	for _, b := range r.Blockers {
+		fmt.Fprintf(w, "- %s %s (%s): %s\n", stateEmoji(b.PR), b.PR.Title, prRef(b.PR), strings.Join(b.Reasons, "、"))
+	}
+}
+
+func renderStandupMarkdown(w io.Writer, r standupReport) {
+	item := func(pr client.PullRequest) string {
+		return fmt.Sprintf("- %s [%s](%s) %s", stateEmoji(pr), markdownEscape(pr.Title), pr.HTMLURL, prRef(pr))
+	}
+
+	fmt.Fprintf(w, "### Yesterday (%s)\n\n", r.Yesterday)
+	if len(r.Done) == 0 {
+		fmt.Fprintln(w, "- なし")
+	}
+	for _, pr := range r.Done {
+		fmt.Fprintln(w, item(pr))
+	}
+
+	fmt.Fprint(w, "\n### Today\n\n")
+	if len(r.Doing) == 0 {
+		fmt.Fprintln(w, "- なし")
+	}
+	for _, pr := range r.Doing {
+		fmt.Fprintln(w, item(pr))
+	}
+
+	fmt.Fprint(w, "\n### Blockers\n\n")
+	if len(r.Blockers) == 0 {
+		fmt.Fprintln(w, "- なし")
+	}
+	for _, b := range r.Blockers {
+		fmt.Fprintf(w, "%s: %s\n", item(b.PR), strings.Join(b.Reasons, "、"))
+	}
+}
+
+func renderStandupSlack(w io.Writer, r standupReport) {
+	item := func(pr client.PullRequest) string {
+		return fmt.Sprintf("• %s <%s|%s> %s", stateEmoji(pr), pr.HTMLURL, slackEscape(pr.Title), prRef(pr))
+	}
+
+	fmt.Fprintf(w, "*Yesterday (%s)*\n", r.Yesterday)
+	if len(r.Done) == 0 {
+		fmt.Fprintln(w, "• なし")
+	}
+	for _, pr := range r.Done {
+		fmt.Fprintln(w, item(pr))
+	}
+
+	fmt.Fprint(w, "\n*Today*\n")
+	if len(r.Doing) == 0 {
+		fmt.Fprintln(w, "• なし")
+	}
+	for _, pr := range r.Doing {
+		fmt.Fprintln(w, item(pr))
+	}
+
+	fmt.Fprint(w, "\n*Blockers*\n")
+	if len(r.Blockers) == 0 {
+		fmt.Fprintln(w, "• なし")
+	}
+	for _, b := range r.Blockers {
+		fmt.Fprintf(w, "%s: %s\n", item(b.PR), strings.Join(b.Reasons, "、"))
+	}
+}
+
+// markdownEscape はリンクテキストとして崩れないように角括弧をエスケープする
+func markdownEscape(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(s)
+}
+
+// slackEscape はSlackのmrkdwnで制御文字として扱われる文字をエスケープする
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestFindBlockers(t *testing.T) {
+	prs := []client.PullRequest{
+		{Title: "変更依頼", State: "open", ReviewDecision: "CHANGES_REQUESTED", Reviewers: []string{"alice"}},
+		{Title: "CI失敗", State: "open", CheckState: client.CheckStateFailure, RequestedReviewers: []string{"bob"}},
+		{Title: "レビュアーなし", State: "open"},
+		{Title: "順調", State: "open", ReviewDecision: "APPROVED", Reviewers: []string{"alice"}, CheckState: client.CheckStateSuccess},
+		{Title: "ドラフト", State: "open", Draft: true},
+	}
+
+	blockers := findBlockers(prs)
+	if len(blockers) != 3 {
+		t.Fatalf("findBlockers() returned %d blockers, want 3", len(blockers))
+	}
+	expected := []string{"変更依頼あり", "CI失敗", "レビュアー未設定"}
+	for i, b := range blockers {
+		if len(b.Reasons) != 1 || b.Reasons[0] != expected[i] {
+			t.Errorf("blockers[%d].Reasons = %v, want [%s]", i, b.Reasons, expected[i])
+		}
+	}
+}
+
+func TestRenderStandupSlack(t *testing.T) {
+	pr := client.PullRequest{Title: "Fix <script> & more", HTMLURL: "https://github.com/owner/repo/pull/1", State: "open", Number: 1}
+	pr.Repository.FullName = "owner/repo"
+
+	var buf bytes.Buffer
+	renderStandupSlack(&buf, standupReport{Yesterday: "2024-02-02", Doing: []client.PullRequest{pr}})
+
+	out := buf.String()
+	if !strings.Contains(out, "*Yesterday (2024-02-02)*\n• なし") {
+		t.Errorf("output does not contain empty yesterday section:\n%s", out)
+	}
+	if !strings.Contains(out, "<https://github.com/owner/repo/pull/1|Fix &lt;script&gt; &amp; more> owner/repo#1") {
+		t.Errorf("output does not contain escaped PR link:\n%s", out)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+// parseTeam は--teamの"org/team-slug"を組織とチームのスラッグに分ける。空の場合は両方とも空を返す
+func parseTeam(value string) (string, string, error) {
+	if value == "" {
+		return "", "", nil
+	}
+	org, slug, ok := strings.Cut(value, "/")
+	if !ok || org == "" || slug == "" || strings.Contains(slug, "/") {
+		return "", "", fmt.Errorf("不正なチーム: %s（org/team-slug形式で指定してください）", value)
+	}
+	return org, slug, nil
+}
+
+// uniqueUsers は空白と先頭の@を取り除き、大文字小文字を区別せずに重複を除いたユーザー名を返す
+func uniqueUsers(values []string) []string {
+	var users []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		user := strings.TrimPrefix(strings.TrimSpace(value), "@")
+		if user == "" || seen[strings.ToLower(user)] {
+			continue
+		}
+		seen[strings.ToLower(user)] = true
+		users = append(users, user)
+	}
+	return users
+}
+
+// groupByUser はPRを活動を判定したユーザーごとに分け、usersの順に並べる。PRがないユーザーは含めない
+func groupByUser(prs []client.PullRequest, users []string) ([]string, map[string][]client.PullRequest) {
+	byUser := make(map[string][]client.PullRequest)
+	for _, pr := range prs {
+		byUser[pr.User] = append(byUser[pr.User], pr)
+	}
+	var ordered []string
+	for _, user := range users {
+		if len(byUser[user]) > 0 {
+			ordered = append(ordered, user)
+		}
+	}
+	return ordered, byUser
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestParseTeam(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		wantOrg  string
+		wantSlug string
+		wantErr  bool
+	}{
+		{name: "未指定", value: ""},
+		{name: "org/slug形式", value: "acme/backend", wantOrg: "acme", wantSlug: "backend"},
+		{name: "スラッシュなし", value: "backend", wantErr: true},
+		{name: "スラッグが空", value: "acme/", wantErr: true},
+		{name: "階層が多い", value: "acme/backend/api", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org, slug, err := parseTeam(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTeam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if org != tt.wantOrg || slug != tt.wantSlug {
+				t.Errorf("parseTeam() = %s, %s, want %s, %s", org, slug, tt.wantOrg, tt.wantSlug)
+			}
+		})
+	}
+}
+
+func TestUniqueUsers(t *testing.T) {
+	got := uniqueUsers([]string{"alice", " @bob", "Alice", ""})
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uniqueUsers() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByUser(t *testing.T) {
+	prs := []client.PullRequest{
+		{Number: 1, User: "bob"},
+		{Number: 2, User: "alice"},
+		{Number: 3, User: "bob"},
+	}
+
+	users, byUser := groupByUser(prs, []string{"alice", "bob", "carol"})
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(users, want) {
+		t.Errorf("groupByUser() users = %v, want %v", users, want)
+	}
+	if len(byUser["bob"]) != 2 || byUser["bob"][0].Number != 1 {
+		t.Errorf("groupByUser() bob = %+v, want #1, #3", byUser["bob"])
+	}
+}
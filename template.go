@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/pkg/template"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/cli/go-gh/pkg/text"
+	"github.com/hiroyannnn/gh-pr-digest/config"
+	"github.com/spf13/cobra"
+)
+
+// templateDigest はテンプレートに渡すデータ。キーはJSON出力と同じ名前を使う
+type templateDigest struct {
+	digestJSON
+	Title string `json:"title"`
+	// 期間（YYYY-MM-DD形式）。開始日を指定しなかった場合は空
+	Since string `json:"since,omitempty"`
+	Until string `json:"until"`
+	// チームのダイジェストの対象ユーザー
+	Users []string `json:"users,omitempty"`
+}
+
+// loadTemplate は--template、--template-file、--template-nameのいずれかからテンプレートを読み込む。
+// どれも指定されていない場合は空文字列を返す。
+func loadTemplate(cmd *cobra.Command) (string, error) {
+	inline, _ := cmd.Flags().GetString("template")
+	file, _ := cmd.Flags().GetString("template-file")
+	name, _ := cmd.Flags().GetString("template-name")
+
+	switch {
+	case inline != "":
+		return inline, nil
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("テンプレートの読み込みに失敗: %w", err)
+		}
+		return string(data), nil
+	case name != "":
+		path := filepath.Join(config.TemplateDir(), name+".tmpl")
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("テンプレートが見つかりません: %s（%s）", name, path)
+		}
+		if err != nil {
+			return "", fmt.Errorf("テンプレートの読み込みに失敗: %w", err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+// outputTemplate はユーザー定義のテンプレートでPRを出力する
+func outputTemplate(w io.Writer, tmpl string, d digest) error {
+	rng := d.Range
+	data := templateDigest{
+		digestJSON: newDigestJSON(d.PRs, d.Failures),
+		Title:      d.title(),
+		Until:      rng.LastDay().Format("2006-01-02"),
+		Users:      d.Users,
+	}
+	if !rng.Since.IsZero() {
+		data.Since = rng.Since.Format("2006-01-02")
+	}
+
+	// go-ghのテンプレートと同じくJSONに変換したデータを渡す
+	input, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("テンプレート用データの作成に失敗: %w", err)
+	}
+
+	t := term.FromEnv()
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+	tp := template.New(w, width, t.IsColorEnabled())
+	tp.Funcs(templateFuncs(rng.Location()))
+	if err := tp.Parse(tmpl); err != nil {
+		return fmt.Errorf("テンプレートの解析に失敗: %w", err)
+	}
+	if err := tp.Execute(bytes.NewReader(input)); err != nil {
+		return fmt.Errorf("テンプレートの実行に失敗: %w", err)
+	}
+	return tp.Flush()
+}
+
+// templateFuncs はgo-ghの関数（timeago、truncate、color、hyperlink、timefmt、join、pluck、
+// tablerow）に加えてテンプレートで使える関数
+func templateFuncs(loc *time.Location) map[string]interface{} {
+	return map[string]interface{}{
+		"groupBy":   groupByFunc,
+		"pluralize": pluralizeFunc,
+		// timefmtと異なり--tzのタイムゾーンで表示する
+		"date": func(layout, input string) (string, error) {
+			t, err := time.Parse(time.RFC3339, input)
+			if err != nil {
+				return "", err
+			}
+			return t.In(loc).Format(layout), nil
+		},
+	}
+}
+
+// groupByFunc は"repository.full_name"のようなドット区切りのフィールドの値でまとめ、
+// キーの順に{"key": 値, "items": [...]}の一覧を返す
+func groupByFunc(field string, input []interface{}) ([]interface{}, error) {
+	groups := make(map[string][]interface{})
+	for _, item := range input {
+		value, err := lookupField(item, field)
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprint(value)
+		if value == nil {
+			key = ""
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, map[string]interface{}{
+			"key":   key,
+			"items": groups[key],
+		})
+	}
+	return result, nil
+}
+
+func lookupField(item interface{}, field string) (interface{}, error) {
+	value := item
+	for _, name := range strings.Split(field, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("groupBy: %sを参照できません", field)
+		}
+		value = m[name]
+	}
+	return value, nil
+}
+
+// pluralizeFunc は"3 PRs"のように件数と単語を返す
+func pluralizeFunc(num interface{}, thing string) (string, error) {
+	switch n := num.(type) {
+	case int:
+		return text.Pluralize(n, thing), nil
+	case float64:
+		return text.Pluralize(int(n), thing), nil
+	default:
+		return "", fmt.Errorf("pluralize: 数値ではありません: %v", num)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestOutputTemplate(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newPR := func(repo string, number int, title string, updatedAt time.Time) client.PullRequest {
+		pr := client.PullRequest{Title: title, Number: number, State: "open", UpdatedAt: updatedAt}
+		pr.Repository.FullName = repo
+		return pr
+	}
+	prs := []client.PullRequest{
+		newPR("owner/web", 3, "画面の修正", time.Date(2024, 1, 22, 16, 0, 0, 0, time.UTC)),
+		newPR("owner/api", 1, "新機能の追加", time.Date(2024, 1, 23, 1, 0, 0, 0, time.UTC)),
+		newPR("owner/web", 2, "不要なコードの削除", time.Date(2024, 1, 24, 1, 0, 0, 0, time.UTC)),
+	}
+	rng := client.DateRange{
+		Since: time.Date(2024, 1, 22, 0, 0, 0, 0, tokyo),
+		Until: time.Date(2024, 1, 27, 0, 0, 0, 0, tokyo),
+	}
+
+	tests := []struct {
+		name     string
+		tmpl     string
+		expected string
+	}{
+		{
+			name:     "期間と件数",
+			tmpl:     `{{.since}}〜{{.until}}: {{pluralize (len .pull_requests) "PR"}}`,
+			expected: "2024-01-22〜2024-01-26: 3 PRs",
+		},
+		{
+			name:     "リポジトリごとにまとめる",
+			tmpl:     `{{range groupBy "repository.full_name" .pull_requests}}{{.key}}:{{range .items}} #{{.number}}{{end}};{{end}}`,
+			expected: "owner/api: #1;owner/web: #3 #2;",
+		},
+		{
+			name:     "タイムゾーンに合わせた日付",
+			tmpl:     `{{range .pull_requests}}{{date "01/02 15:04" .updated_at}} {{truncate 6 .title}}|{{end}}`,
+			expected: "01/23 01:00 画... |01/23 10:00 新... |01/24 10:00 不... |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := outputTemplate(&buf, tt.tmpl, digest{PRs: prs, Range: rng, Ranged: true}); err != nil {
+				t.Fatalf("outputTemplate() error = %v", err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("outputTemplate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/pkg/text"
+	"github.com/hiroyannnn/gh-pr-digest/client"
+	"github.com/hiroyannnn/gh-pr-digest/config"
+	"github.com/spf13/cobra"
+)
+
+func newWeeklyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "weekly",
+		Short: "Show a weekly report with per-day and per-repository counts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWeekly(cmd)
+		},
+	}
+
+	cmd.Flags().String("week", "this-week", "対象の週（this-week、last-week、2026-W41など。--sinceと同じ形式）")
+	cmd.Flags().StringSlice("role", []string{"author"}, "自分との関係（author/reviewer/commenter/assignee/involves、カンマ区切りで複数指定可）")
+	cmd.Flags().String("format", "text", "出力形式（text/markdown/json）")
+
+	return cmd
+}
+
+// weeklyReport は週報の内容
+type weeklyReport struct {
+	Since string      `json:"since"`
+	Until string      `json:"until"`
+	Days  []weeklyDay `json:"days"`
+	// リポジトリ名の順
+	Repositories []weeklyCounts `json:"repositories"`
+	Total        weeklyCounts   `json:"total"`
+}
+
+// weeklyCounts は期間内に作成・マージ・クローズされたPRと、まだオープンなPRの件数。
+// 追加・削除した行数はリポジトリごとと合計でのみ集計する
+type weeklyCounts struct {
+	Name      string `json:"name,omitempty"`
+	Opened    int    `json:"opened"`
+	Merged    int    `json:"merged"`
+	Closed    int    `json:"closed"`
+	Open      int    `json:"open"`
+	Additions int    `json:"additions,omitempty"`
+	Deletions int    `json:"deletions,omitempty"`
+}
+
+func (c *weeklyCounts) add(o weeklyCounts) {
+	c.Opened += o.Opened
+	c.Merged += o.Merged
+	c.Closed += o.Closed
+	c.Open += o.Open
+	c.Additions += o.Additions
+	c.Deletions += o.Deletions
+}
+
+// weeklyDay はその日に自分が活動したPRと件数。Openはその日に活動したPRのうちまだオープンなもの
+type weeklyDay struct {
+	Date string `json:"date"`
+	day  time.Time
+	weeklyCounts
+	PullRequests []client.PullRequest `json:"pull_requests"`
+}
+
+func runWeekly(cmd *cobra.Command) error {
+	org, _ := cmd.Flags().GetString("org")
+	week, _ := cmd.Flags().GetString("week")
+	roleValues, _ := cmd.Flags().GetStringSlice("role")
+	format, _ := cmd.Flags().GetString("format")
+
+	roles, err := client.ParseRoles(roleValues)
+	if err != nil {
+		return err
+	}
+	loc, err := resolveLocation(cmd)
+	if err != nil {
+		return err
+	}
+	rng, err := client.ParseDateRange(week, week, loc)
+	if err != nil {
+		return err
+	}
+
+	filters, err := parseFilters(cmd)
+	if err != nil {
+		return err
+	}
+
+	repos, repoHost, err := resolveRepos(cmd)
+	if err != nil {
+		return err
+	}
+	clients, err := newClients(cmd, repoHost)
+	if err != nil {
+		return err
+	}
+
+	opts := client.SearchOptions{
+		Org:     org,
+		Repos:   repos,
+		Filters: filters,
+		Range:   rng,
+		Roles:   roles,
+	}
+	prs, err := clients.fetch(func(c *client.PRClient) ([]client.PullRequest, error) {
+		return c.FetchTodaysPRs(opts)
+	})
+	if err != nil {
+		return err
+	}
+	printDebugSummary(cmd, clients)
+	printWarnings(clients)
+	printFailures(os.Stderr, clients.Errors())
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	applySizes(prs, cfg.Sizes())
+
+	report := buildWeeklyReport(prs, rng)
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "markdown":
+		renderWeeklyMarkdown(os.Stdout, report)
+	case "text":
+		renderWeeklyText(os.Stdout, report)
+	default:
+		return fmt.Errorf("不明な出力形式: %s（text/markdown/jsonのいずれかを指定してください）", format)
+	}
+	return nil
+}
+
+// buildWeeklyReport はPRを自分が活動した日ごとに分け、日ごと・リポジトリごとに件数を集計する
+func buildWeeklyReport(prs []client.PullRequest, rng client.DateRange) weeklyReport {
+	loc := rng.Location()
+	report := weeklyReport{
+		Since: rng.Since.Format("2006-01-02"),
+		Until: rng.LastDay().Format("2006-01-02"),
+	}
+
+	dayIndex := make(map[string]int)
+	for d := rng.Since; d.Before(rng.Until); d = d.AddDate(0, 0, 1) {
+		dayIndex[d.Format("2006-01-02")] = len(report.Days)
+		report.Days = append(report.Days, weeklyDay{Date: d.Format("2006-01-02"), day: d, PullRequests: []client.PullRequest{}})
+	}
+	day := func(t time.Time) *weeklyDay {
+		if i, ok := dayIndex[t.In(loc).Format("2006-01-02")]; ok {
+			return &report.Days[i]
+		}
+		return nil
+	}
+
+	repos, byRepo := groupByRepository(prs)
+	for _, repo := range repos {
+		counts := weeklyCounts{Name: repo}
+		for _, pr := range byRepo[repo] {
+			if rng.Contains(pr.CreatedAt) {
+				counts.Opened++
+				if d := day(pr.CreatedAt); d != nil {
+					d.Opened++
+				}
+			}
+			if pr.ClosedAt != nil && rng.Contains(*pr.ClosedAt) {
+				d := day(*pr.ClosedAt)
+				if pr.Merged {
+					counts.Merged++
+					if d != nil {
+						d.Merged++
+					}
+				} else {
+					counts.Closed++
+					if d != nil {
+						d.Closed++
+					}
+				}
+			}
+			if pr.State == "open" {
+				counts.Open++
+			}
+			counts.Additions += pr.Additions
+			counts.Deletions += pr.Deletions
+
+			// 同じ日に複数の活動があっても1件として数える
+			seen := make(map[*weeklyDay]bool)
+			for _, a := range pr.Activities {
+				d := day(a.At)
+				if d == nil || seen[d] {
+					continue
+				}
+				seen[d] = true
+				d.PullRequests = append(d.PullRequests, pr)
+				if pr.State == "open" {
+					d.Open++
+				}
+			}
+		}
+		report.Repositories = append(report.Repositories, counts)
+		report.Total.add(counts)
+	}
+	return report
+}
+
+var weekdays = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+func dayLabel(d time.Time) string {
+	return fmt.Sprintf("%s (%s)", d.Format("01/02"), weekdays[d.Weekday()])
+}
+
+func renderWeeklyText(w io.Writer, r weeklyReport) {
+	fmt.Fprintf(w, "Weekly Report (%s 〜 %s)\n\n", r.Since, r.Until)
+
+	for _, d := range r.Days {
+		fmt.Fprintf(w, "%s  活動 %d件 / 作成 %d / マージ %d / クローズ %d / オープン %d\n",
+			dayLabel(d.day), len(d.PullRequests), d.Opened, d.Merged, d.Closed, d.Open)
+		for _, pr := range d.PullRequests {
+			fmt.Fprintf(w, "  %s %s %s [%s]\n", stateEmoji(pr), pr.Title, prRef(pr), sizeSummary(pr))
+		}
+	}
+
+	fmt.Fprintln(w)
+	row := func(name string, c weeklyCounts) []string {
+		return []string{name, strconv.Itoa(c.Opened), strconv.Itoa(c.Merged), strconv.Itoa(c.Closed), strconv.Itoa(c.Open),
+			"+" + strconv.Itoa(c.Additions), "-" + strconv.Itoa(c.Deletions)}
+	}
+	rows := [][]string{{"リポジトリ", "作成", "マージ", "クローズ", "オープン", "追加", "削除"}}
+	for _, c := range r.Repositories {
+		rows = append(rows, row(c.Name, c))
+	}
+	rows = append(rows, row("合計", r.Total))
+	writeTable(w, rows)
+}
+
+// writeTable は表示幅に合わせて列を揃えた表を出力する。1列目以外は右寄せにする
+func writeTable(w io.Writer, rows [][]string) {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if width := text.DisplayWidth(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-text.DisplayWidth(cell))
+			if i == 0 {
+				b.WriteString(cell + pad)
+			} else {
+				b.WriteString("  " + pad + cell)
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+func renderWeeklyMarkdown(w io.Writer, r weeklyReport) {
+	fmt.Fprintf(w, "## Weekly Report (%s 〜 %s)\n", r.Since, r.Until)
+
+	for _, d := range r.Days {
+		fmt.Fprintf(w, "\n### %s\n\n", dayLabel(d.day))
+		fmt.Fprintf(w, "作成 %d / マージ %d / クローズ %d / オープン %d\n", d.Opened, d.Merged, d.Closed, d.Open)
+		if len(d.PullRequests) > 0 {
+			fmt.Fprintln(w)
+		}
+		for _, pr := range d.PullRequests {
+			fmt.Fprintf(w, "- %s [%s](%s) %s\n", stateEmoji(pr), markdownEscape(pr.Title), pr.HTMLURL, prRef(pr))
+		}
+	}
+
+	fmt.Fprint(w, "\n### 集計\n\n")
+	fmt.Fprintln(w, "| リポジトリ | 作成 | マージ | クローズ | オープン | 追加 | 削除 |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, c := range r.Repositories {
+		fmt.Fprintf(w, "| %s | %d | %d | %d | %d | +%d | -%d |\n", c.Name, c.Opened, c.Merged, c.Closed, c.Open, c.Additions, c.Deletions)
+	}
+	fmt.Fprintf(w, "| **合計** | **%d** | **%d** | **%d** | **%d** | **+%d** | **-%d** |\n",
+		r.Total.Opened, r.Total.Merged, r.Total.Closed, r.Total.Open, r.Total.Additions, r.Total.Deletions)
+}
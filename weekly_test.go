@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hiroyannnn/gh-pr-digest/client"
+)
+
+func TestBuildWeeklyReport(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, tokyo)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	newPR := func(repo string, number int, state string, merged bool, createdAt time.Time, closedAt *time.Time, activityDays ...int) client.PullRequest {
+		pr := client.PullRequest{Title: "PR", Number: number, State: state, Merged: merged, CreatedAt: createdAt, ClosedAt: closedAt,
+			Additions: number * 10, Deletions: number}
+		pr.Repository.FullName = repo
+		for _, d := range activityDays {
+			pr.Activities = append(pr.Activities, client.Activity{Kind: client.ContributionCommitted, At: at(d, 10)})
+		}
+		return pr
+	}
+
+	// 2024-01-22（月）〜 2024-01-28（日）
+	rng := client.DateRange{Since: at(22, 0), Until: at(29, 0)}
+	prs := []client.PullRequest{
+		// 月曜日に作成して水曜日にマージ
+		newPR("owner/api", 1, "closed", true, at(22, 9), ptr(at(24, 18)), 22, 24),
+		// 先週作成して火曜日にクローズ（日本時間では火曜日の朝）
+		newPR("owner/api", 2, "closed", false, at(15, 9), ptr(time.Date(2024, 1, 22, 23, 30, 0, 0, time.UTC)), 23),
+		// 火曜日に作成してまだオープン（同じ日の複数の活動は1件）
+		newPR("owner/web", 3, "open", false, at(23, 9), nil, 23, 23),
+	}
+
+	report := buildWeeklyReport(prs, rng)
+
+	if report.Since != "2024-01-22" || report.Until != "2024-01-28" {
+		t.Errorf("range = %s 〜 %s", report.Since, report.Until)
+	}
+	if len(report.Days) != 7 {
+		t.Fatalf("days = %d, want 7", len(report.Days))
+	}
+
+	days := []struct {
+		date   string
+		prs    int
+		opened int
+		merged int
+		closed int
+		open   int
+	}{
+		{date: "2024-01-22", prs: 1, opened: 1},
+		{date: "2024-01-23", prs: 2, opened: 1, closed: 1, open: 1},
+		{date: "2024-01-24", prs: 1, merged: 1},
+		{date: "2024-01-25"},
+	}
+	for i, want := range days {
+		got := report.Days[i]
+		if got.Date != want.date || len(got.PullRequests) != want.prs || got.Opened != want.opened ||
+			got.Merged != want.merged || got.Closed != want.closed || got.Open != want.open {
+			t.Errorf("days[%d] = %s prs=%d %+v, want %+v", i, got.Date, len(got.PullRequests), got.weeklyCounts, want)
+		}
+	}
+
+	repos := []weeklyCounts{
+		{Name: "owner/api", Opened: 1, Merged: 1, Closed: 1, Additions: 30, Deletions: 3},
+		{Name: "owner/web", Opened: 1, Open: 1, Additions: 30, Deletions: 3},
+	}
+	if len(report.Repositories) != len(repos) {
+		t.Fatalf("repositories = %+v", report.Repositories)
+	}
+	for i, want := range repos {
+		if report.Repositories[i] != want {
+			t.Errorf("repositories[%d] = %+v, want %+v", i, report.Repositories[i], want)
+		}
+	}
+	if want := (weeklyCounts{Opened: 2, Merged: 1, Closed: 1, Open: 1, Additions: 60, Deletions: 6}); report.Total != want {
+		t.Errorf("total = %+v, want %+v", report.Total, want)
+	}
+
+	var buf bytes.Buffer
+	renderWeeklyText(&buf, report)
+	if !strings.Contains(buf.String(), "リポジトリ  作成  マージ  クローズ  オープン  追加  削除\nowner/api      1       1         1         0   +30    -3\n") {
+		t.Errorf("table is not aligned:\n%s", buf.String())
+	}
+}